@@ -15,16 +15,24 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/build"
+	"github.com/fireflyframework/fireflyframework-cli/internal/cache"
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 	"github.com/fireflyframework/fireflyframework-cli/internal/java"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/report"
+	"github.com/fireflyframework/fireflyframework-cli/internal/runlog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/setup"
 	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
 	"github.com/spf13/cobra"
@@ -33,9 +41,24 @@ import (
 var (
 	buildAll       bool
 	buildRepo      string
+	buildUpTo      string
 	buildDryRun    bool
 	buildSkipTests bool
 	buildJDKPath   string
+	buildJobs      int
+	buildMemPerJob int
+	buildNoCache   bool
+	buildMavenArgs []string
+	buildReactor   bool
+	buildNoIncr    bool
+	buildWatch     bool
+
+	buildReport     string
+	buildReportFile string
+
+	buildFailFast      bool
+	buildKeepGoing     bool
+	buildForceContinue bool
 )
 
 var buildCmd = &cobra.Command{
@@ -51,9 +74,14 @@ The build process runs through the following phases:
     Verifies that Git, Maven, and Java are installed and available.
 
   Phase 1 — Change Detection
-    Compares HEAD commit SHAs against the last-build manifest
-    (~/.flywork/build-manifest.json). Repos with different SHAs are
-    considered changed.
+    Compares each repo's working-tree fingerprint — the HEAD commit SHA plus
+    a content hash of uncommitted and untracked files (honouring .gitignore,
+    ignoring target/) — against the last-build manifest
+    (~/.flywork/build-manifest.json). Repos with a different fingerprint are
+    considered changed, so local edits trigger a rebuild. So are repos
+    whose artifacts recorded at the last build are missing from ~/.m2 or no
+    longer match the recorded checksums. With -v the plan shows why each
+    repo is considered changed.
 
   Phase 2 — Build Plan
     Displays affected repos grouped by DAG layer. Directly changed repos
@@ -61,22 +89,83 @@ The build process runs through the following phases:
 
   Phase 3 — DAG Build
     Runs 'mvn clean install' layer-by-layer with progress bars and per-repo
-    spinners showing elapsed time.
+    spinners showing elapsed time. With --jobs N, up to N repos are built at
+    once, and each repo starts as soon as its own dependencies are built
+    rather than waiting for the whole previous layer.
+
+    When a repo fails, its transitive dependents are not built against the
+    stale artifacts in ~/.m2; they are reported as blocked (--keep-going,
+    the default). Use --fail-fast to start nothing new after the first
+    failure, or --force-continue to build every repo regardless.
+
+    Before building a repo, the build cache (~/.flywork/cache, plus the
+    shared cache at cache_url if configured) is consulted. Its key combines
+    the repo's fingerprint, the keys of its dependencies and the JDK; on a
+    hit the cached jars and poms are restored into ~/.m2 and Maven is not
+    run. Successful builds are added to the cache. Use --no-cache to always
+    run Maven, or 'flywork config set build_cache false' to disable it.
+
+    A multi-module repo whose own sources changed since a clean, successful
+    build is built incrementally: only the submodules with changed files
+    are passed to 'mvn -pl <modules> -amd', which also rebuilds the modules
+    depending on them. It is built in full when its root pom.xml, .mvn/ or
+    mvnw, its overlay settings or files outside every submodule changed,
+    or when one of its dependencies is rebuilt. Use --no-incremental to
+    always build whole repos.
+
+    With --reactor, the affected repos are instead listed as modules of a
+    generated aggregator POM and built by a single 'mvn -T <jobs> clean
+    install', so the JVM starts and dependencies resolve once and Maven's
+    reactor orders and parallelizes the modules. Per-repo results are read
+    from the reactor summary. Overlay maven_args and skip_tests settings do
+    not apply to a reactor build.
+
+    Ctrl-C stops the build: the running Maven process groups are terminated
+    and their repos are recorded as interrupted, so the next build picks
+    them up again.
 
   Phase 4 — Summary
-    Reports built/skipped/failed counts, total time, and log file locations
-    for any failures.
+    Reports built/cached/skipped/failed/blocked counts, total time, and log file
+    locations for any failures. The Maven output of each failed repo is
+    analyzed: the summary lists the failing module, compiler errors
+    (file:line and message), failed test classes, unresolved artifact
+    coordinates and enforcer rule violations, with a remediation hint for
+    known failure signatures (e.g. a parent POM that is not installed).
+
+With --report json|junit|html, a machine-readable report of the run is
+written to --report-file (default: flywork-build-report.<ext>). It lists the
+status (built, cached, skipped, failed, blocked or interrupted), commit SHA,
+DAG layer, start time, duration and log path of every repo. The HTML report
+adds a Gantt-style timeline of the DAG execution with the critical path
+outlined and the idle worker time.
+
+With --watch, flywork keeps running after the build and watches repos_path
+for file changes (ignoring target/ and .git). Once the tree has been quiet
+for a moment, the changed files are mapped to their repos and those repos
+and their affected dependents are rebuilt. A build still running when new
+changes arrive is cancelled and restarted with the combined set. Press
+Ctrl+C to stop watching.
 
 Use --all to ignore change detection and rebuild everything. Use --repo to
-target a specific repository and its downstream dependents. Use --dry-run to
-preview the build plan without executing it.
+target a specific repository and its downstream dependents. Use --up-to to
+build the changed upstream dependencies of a repository in layer order and
+then the repository itself. Use --dry-run to preview the build plan without
+executing it.
 
 Examples:
   flywork build                     Build changed repos + affected dependents
   flywork build --all               Rebuild everything
   flywork build --repo <name>       Build a specific repo and its dependents
+  flywork build --up-to <name>      Build a repo's changed dependencies, then the repo
   flywork build --dry-run           Preview build plan without building
   flywork build --skip-tests        Skip tests during Maven install
+  flywork build --all --jobs 4      Build up to 4 repos in parallel
+  flywork build --fail-fast         Stop at the first failure
+  flywork build --no-cache          Run Maven even for cached builds
+  flywork build --reactor -j 4      Build everything in one Maven reactor
+  flywork build --no-incremental    Build whole repos, not changed submodules
+  flywork build --watch             Rebuild affected repos whenever files change
+  flywork build --report junit      Write flywork-build-report.xml for CI
   flywork build --jdk /path/to/jdk  Use a specific JAVA_HOME`,
 	RunE: runBuild,
 }
@@ -84,9 +173,20 @@ Examples:
 func init() {
 	buildCmd.Flags().BoolVar(&buildAll, "all", false, "Rebuild everything (ignore change detection)")
 	buildCmd.Flags().StringVar(&buildRepo, "repo", "", "Build a specific repo and its dependents")
+	buildCmd.Flags().StringVar(&buildUpTo, "up-to", "", "Build a repo's changed upstream dependencies, then the repo itself")
+	buildCmd.MarkFlagsMutuallyExclusive("repo", "up-to")
 	buildCmd.Flags().BoolVar(&buildDryRun, "dry-run", false, "Show what would be built without building")
 	buildCmd.Flags().BoolVar(&buildSkipTests, "skip-tests", false, "Skip running tests during Maven install")
 	buildCmd.Flags().StringVar(&buildJDKPath, "jdk", "", "Explicit JAVA_HOME path")
+	buildCmd.Flags().BoolVar(&buildNoCache, "no-cache", false, "Do not restore builds from the build cache or store new ones")
+	buildCmd.Flags().BoolVar(&buildReactor, "reactor", false, "Build the affected repos in a single Maven reactor (mvn -T <jobs>)")
+	buildCmd.Flags().BoolVar(&buildNoIncr, "no-incremental", false, "Always build whole repos instead of only their changed submodules")
+	buildCmd.Flags().BoolVar(&buildWatch, "watch", false, "Keep watching repos_path and rebuild the affected repos on file changes")
+	buildCmd.MarkFlagsMutuallyExclusive("watch", "dry-run")
+	addMavenArgFlag(buildCmd, &buildMavenArgs)
+	addParallelFlags(buildCmd, &buildJobs, &buildMemPerJob)
+	addFailurePolicyFlags(buildCmd, &buildFailFast, &buildKeepGoing, &buildForceContinue)
+	addReportFlags(buildCmd, &buildReport, &buildReportFile)
 	rootCmd.AddCommand(buildCmd)
 }
 
@@ -94,6 +194,12 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	p := ui.NewPrinter()
 	overallStart := time.Now()
 
+	var err error
+	buildReport, buildReportFile, err = report.ResolveTarget("build", buildReport, buildReportFile)
+	if err != nil {
+		return err
+	}
+
 	p.Header("Smart Build")
 
 	// ═════════════════════════════════════════════════════════════════════════
@@ -143,7 +249,10 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	// ═════════════════════════════════════════════════════════════════════════
 	p.StageHeader(1, "Change Detection")
 
-	g := dag.FrameworkGraph()
+	g, err := dag.Load(cfg.ReposPath)
+	if err != nil {
+		return fmt.Errorf("dependency graph error: %w", err)
+	}
 
 	manifest, err := build.LoadManifest(build.DefaultManifestPath())
 	if err != nil {
@@ -154,7 +263,11 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		manifest = build.NewManifest()
 	}
 
-	changed := build.DetectChanges(g, cfg.ReposPath, manifest)
+	reasons := build.ChangeReasons(g, cfg.ReposPath, manifest)
+	changed := make(map[string]bool, len(reasons))
+	for repo := range reasons {
+		changed[repo] = true
+	}
 	affected := build.TransitiveClosure(g, changed)
 
 	if buildAll {
@@ -168,13 +281,25 @@ func runBuild(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("unknown repository: %s", buildRepo)
 		}
 		p.Info(fmt.Sprintf("Mode: targeted build for %s + dependents", buildRepo))
+	} else if buildUpTo != "" {
+		p.Info(fmt.Sprintf("Mode: build up to %s (changed upstream dependencies + repo)", buildUpTo))
 	} else {
 		p.Info(fmt.Sprintf("%d repos changed, %d affected by dependencies", len(changed), len(affected)-len(changed)))
 	}
 
+	if buildUpTo != "" {
+		if !g.HasNode(buildUpTo) {
+			return fmt.Errorf("unknown repository: %s", buildUpTo)
+		}
+		affected = build.ScopeUpTo(g, buildUpTo, affected)
+	}
+
 	if len(affected) == 0 && !buildAll {
 		p.Newline()
 		p.Success("Everything is up to date — nothing to build")
+		if buildWatch {
+			return watchBuild(p, cfg, newBuildOptions(p, cfg))
+		}
 		return nil
 	}
 
@@ -217,6 +342,9 @@ func runBuild(cmd *cobra.Command, args []string) error {
 			if changed[repo] {
 				marker = ui.StyleWarning.Render("*")
 			}
+			if verbose && reasons[repo] != "" {
+				short += "  " + ui.StyleMuted.Render(reasons[repo])
+			}
 			fmt.Printf("    %s %s\n", marker, short)
 		}
 		totalToBuild += len(layer)
@@ -231,7 +359,7 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if !ui.Confirm("Proceed with build?", true) {
+	if !buildWatch && !ui.Confirm("Proceed with build?", true) {
 		return nil
 	}
 
@@ -241,67 +369,80 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	p.StageHeader(3, "Building")
 	p.Newline()
 
-	javaHome := buildJDKPath
-	if javaHome == "" && buildJDKPath == "" {
-		selectedHome, jdkErr := setup.SelectJDK(cfg.JavaVersion)
-		if jdkErr != nil {
-			p.Warning(jdkErr.Error() + " — using system default")
-		} else {
-			javaHome = selectedHome
-		}
-	}
-
-	opts := build.BuildOptions{
-		ReposDir:  cfg.ReposPath,
-		JavaHome:  javaHome,
-		SkipTests: buildSkipTests,
-		ForceAll:  buildAll,
-		DryRun:    false,
-	}
-	if buildRepo != "" {
-		opts.TargetRepos = []string{buildRepo}
-	}
+	opts := newBuildOptions(p, cfg)
+	opts.Logs = startRunLog(p, "build", cfg)
+	rec := report.NewRecorder("build", cfg.ReposPath, g, opts.Jobs)
 
 	bar := ui.NewProgressBar(totalToBuild, "built")
-	var activeSpinner *ui.Spinner
-	built, skipped, failed := 0, 0, 0
+	spinner := ui.NewJobSpinner("Building")
+	opts.OnOutput = liveTail(spinner)
+	built, cached, skipped, failed, blocked := 0, 0, 0, 0, 0
 	prevLayer := -1
+	running := make(map[string]bool)
+	var stopped []string
 
+	ctx, stop := interruptContext()
+	defer stop()
 	results, _, err := build.RunDAGBuild(
-		opts,
+		ctx, opts,
 		func(layer int, repo string, idx, total int) {
-			if verbose && layer != prevLayer {
+			running[repo] = true
+			rec.Start(repo)
+			if verbose && !buildReactor && layer > prevLayer {
 				if prevLayer >= 0 {
 					bar.Finish()
 				}
 				p.LayerHeader(layer, len(layers), len(layers[layer]))
 				prevLayer = layer
 			}
-			activeSpinner = ui.NewSpinner(fmt.Sprintf("Building %s...", strings.TrimPrefix(repo, "fireflyframework-")))
-			activeSpinner.Start()
+			spinner.Add(shortName(repo))
 		},
 		func(layer int, repo string, idx, total int, r build.BuildResult) {
-			if activeSpinner != nil {
-				activeSpinner.Stop(r.Error == nil)
-				activeSpinner = nil
+			rec.Finish(buildReportRepo(layer, r))
+			if r.Interrupted {
+				if running[repo] {
+					stopped = append(stopped, repo)
+				}
+				return
 			}
+			delete(running, repo)
+			spinner.Done(shortName(repo), r.Error == nil)
 
 			switch {
 			case r.Skipped:
 				skipped++
+			case r.Cached:
+				cached++
+				if verbose {
+					fmt.Printf("\r\033[K  %s %-45s %s\n", ui.StyleSuccess.Render("↺"), repo,
+						ui.StyleMuted.Render("restored from cache"))
+				}
+			case r.Blocked:
+				blocked++
+				printBlocked(repo, r.BlockedBy)
 			case r.Error != nil:
 				failed++
-				p.Error(fmt.Sprintf("%-45s %s", repo, r.Error))
+				p.Error(fmt.Sprintf("%-45s %s", repo, failureText(r)))
 				if r.LogFile != "" {
 					p.Info(fmt.Sprintf("  Log: %s", r.LogFile))
 				}
 			default:
 				built++
+				if verbose && len(r.Modules) > 0 {
+					fmt.Printf("\r\033[K    %s\n", ui.StyleMuted.Render(fmt.Sprintf("↳ %s: incremental build of %s (-amd)", shortName(repo), strings.Join(r.Modules, ", "))))
+				}
 			}
 
 			bar.Increment()
 		},
 	)
+	opts.Logs.Finish()
+	if errors.Is(err, context.Canceled) {
+		spinner.Stop()
+		printInterrupted(p, stopped, "flywork build")
+		writeReport(p, rec, buildReport, buildReportFile)
+		return errInterrupted
+	}
 	if err != nil {
 		return fmt.Errorf("build error: %w", err)
 	}
@@ -314,20 +455,30 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	elapsed := time.Since(overallStart).Truncate(time.Second)
 
 	status := "Build Complete"
-	if failed > 0 {
+	if failed > 0 || blocked > 0 {
 		status = "Build Incomplete"
 	}
 
 	summaryLines := []string{
 		fmt.Sprintf("Built         %d", built),
+	}
+	if cached > 0 {
+		summaryLines = append(summaryLines, fmt.Sprintf("From cache    %d", cached))
+	}
+	summaryLines = append(summaryLines,
 		fmt.Sprintf("Skipped       %d", skipped),
 		fmt.Sprintf("Failed        %d", failed),
+	)
+	if blocked > 0 {
+		summaryLines = append(summaryLines, fmt.Sprintf("Blocked       %d", blocked))
+	}
+	summaryLines = append(summaryLines,
 		fmt.Sprintf("Layers        %d", len(layers)),
 		fmt.Sprintf("Total time    %s", elapsed),
-	}
+	)
 
-	if failed > 0 {
-		summaryLines = append(summaryLines, fmt.Sprintf("Build logs    %s", build.LogsDir()))
+	if len(opts.Logs.Counts()) > 0 {
+		summaryLines = append(summaryLines, fmt.Sprintf("Build logs    %s", opts.Logs.Dir()))
 	}
 
 	p.SummaryBox(status, summaryLines)
@@ -338,9 +489,291 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		for _, r := range results {
 			if r.Error != nil {
 				p.Error(fmt.Sprintf("  %s — %s", r.Repo, r.Error))
+				printDiagnosis(r.Diagnosis)
 			}
 		}
 	}
+	printBlockedSummary(p, len(results), func(i int) (string, string) {
+		return results[i].Repo, results[i].BlockedBy
+	})
+	writeReport(p, rec, buildReport, buildReportFile)
 
+	if buildWatch {
+		return watchBuild(p, cfg, opts)
+	}
 	return nil
 }
+
+// newBuildOptions returns the build options selected by the build flags,
+// resolving the JDK and reporting the parallelism settings.
+func newBuildOptions(p *ui.Printer, cfg *config.Config) build.BuildOptions {
+	javaHome := buildJDKPath
+	if javaHome == "" && buildJDKPath == "" {
+		selectedHome, jdkErr := setup.SelectJDK(cfg.JavaVersion)
+		if jdkErr != nil {
+			p.Warning(jdkErr.Error() + " — using system default")
+		} else {
+			javaHome = selectedHome
+		}
+	}
+
+	opts := build.BuildOptions{
+		ReposDir:  cfg.ReposPath,
+		JavaHome:  javaHome,
+		SkipTests: buildSkipTests,
+		ForceAll:  buildAll,
+		DryRun:    false,
+		Maven:     mavenSettings(cfg, buildMavenArgs),
+	}
+	if buildRepo != "" {
+		opts.TargetRepos = []string{buildRepo}
+	}
+	opts.UpTo = buildUpTo
+	if buildReactor {
+		// One JVM builds everything, so it gets the whole heap budget.
+		opts.Reactor = true
+		opts.Jobs, opts.HeapMB = buildJobs, heapPerJob(1, buildMemPerJob)
+		opts.HeapSet = buildMemPerJob > 0
+		p.Info(fmt.Sprintf("Reactor: one mvn -T %d clean install over the affected repos", max(buildJobs, 1)))
+	} else {
+		opts.Jobs, opts.HeapMB = buildJobs, heapPerJob(buildJobs, buildMemPerJob)
+		opts.HeapSet = buildMemPerJob > 0
+		printParallelism(p, opts.Jobs, opts.HeapMB)
+	}
+	opts.FailurePolicy = failurePolicy(buildFailFast, buildForceContinue)
+	opts.Incremental = !buildNoIncr
+	if !buildNoCache {
+		opts.Cache = cache.Open(cfg)
+	}
+	return opts
+}
+
+// startRunLog starts the log run of command, keeping the Maven output of
+// every repo it builds under ~/.flywork/logs/<run-id>. Logging is disabled
+// with a warning when the run directory cannot be created.
+func startRunLog(p *ui.Printer, command string, cfg *config.Config) *runlog.Run {
+	run, err := runlog.Start(command, runlog.RetentionFromConfig(cfg))
+	if err != nil {
+		p.Warning("Build logs disabled: " + err.Error())
+		return nil
+	}
+	return run
+}
+
+// liveTail returns the Maven output callback of --verbose runs, which prints
+// each line of output under the spinner, prefixed with its repo. It returns
+// nil otherwise.
+func liveTail(spinner *ui.JobSpinner) func(repo, line string) {
+	if !verbose {
+		return nil
+	}
+	return func(repo, line string) {
+		spinner.Log(shortName(repo), line)
+	}
+}
+
+// failureText describes a failed build in one line: the Maven output
+// analysis when it recognized the failure, otherwise the error.
+func failureText(r build.BuildResult) string {
+	if s := r.Diagnosis.Summary(); s != "" {
+		return s
+	}
+	return r.Error.Error()
+}
+
+// printDiagnosis lists the findings of a failed build's Maven output below
+// its summary line — up to five of each kind — followed by the remediation
+// hints for the failure signatures it recognized.
+func printDiagnosis(d *maven.Diagnosis) {
+	if d == nil {
+		return
+	}
+	if s := d.Summary(); s != "" {
+		fmt.Printf("      %s\n", ui.StyleWarning.Render(s))
+	}
+	for _, line := range d.Details(5) {
+		fmt.Printf("        %s\n", ui.StyleMuted.Render(line))
+	}
+	for _, hint := range d.Hints {
+		fmt.Printf("      %s %s\n", ui.StyleInfo.Render("→"), hint)
+	}
+}
+
+// buildReportRepo converts a build result into its report entry.
+func buildReportRepo(layer int, r build.BuildResult) report.Repo {
+	entry := report.Repo{Name: r.Repo, Layer: layer, LogFile: r.LogFile, Modules: r.Modules, BlockedBy: r.BlockedBy, Diagnosis: r.Diagnosis}
+	switch {
+	case r.Interrupted:
+		entry.Status = report.StatusInterrupted
+	case r.Skipped:
+		entry.Status = report.StatusSkipped
+	case r.Cached:
+		entry.Status = report.StatusCached
+	case r.Blocked:
+		entry.Status = report.StatusBlocked
+	case r.Error != nil:
+		entry.Status = report.StatusFailed
+	default:
+		entry.Status = report.StatusBuilt
+	}
+	if r.Error != nil && !r.Interrupted {
+		entry.Error = r.Error.Error()
+	}
+	return entry
+}
+
+// addReportFlags registers the --report and --report-file flags shared by
+// build, setup, update and publish.
+func addReportFlags(cmd *cobra.Command, format, file *string) {
+	cmd.Flags().StringVar(format, "report", "", "Write a run report: "+strings.Join(report.Formats, ", "))
+	cmd.Flags().StringVar(file, "report-file", "", "Path of the run report (default: flywork-"+cmd.Name()+"-report.<ext>)")
+}
+
+// writeReport writes the report recorded by rec when --report was given,
+// resolved by report.ResolveTarget. Failing to write it is not fatal.
+func writeReport(p *ui.Printer, rec *report.Recorder, format, path string) {
+	if format == "" {
+		return
+	}
+	if err := rec.Report().Write(format, path); err != nil {
+		p.Warning("Could not write report: " + err.Error())
+		return
+	}
+	p.Info(fmt.Sprintf("Report: %s", path))
+}
+
+// addParallelFlags registers the --jobs and --mem-per-job flags shared by
+// build, setup, update and publish.
+func addParallelFlags(cmd *cobra.Command, jobs, memPerJob *int) {
+	cmd.Flags().IntVarP(jobs, "jobs", "j", 1, "Number of independent repos to build in parallel")
+	cmd.Flags().IntVar(memPerJob, "mem-per-job", 0, "Maven heap per parallel build in MB, overriding -Xmx in MAVEN_OPTS (default: that -Xmx, else half of the RAM split across jobs)")
+}
+
+// addMavenArgFlag registers the repeatable --maven-arg flag shared by build,
+// setup, update and publish.
+func addMavenArgFlag(cmd *cobra.Command, args *[]string) {
+	cmd.Flags().StringArrayVar(args, "maven-arg", nil, "Extra argument passed to every Maven invocation (repeatable)")
+}
+
+// mavenSettings returns the global Maven settings of cfg with the
+// --maven-arg values appended to their arguments.
+func mavenSettings(cfg *config.Config, args []string) maven.Settings {
+	return cfg.Maven.Merge(maven.Settings{Args: args})
+}
+
+// heapPerJob returns the -Xmx budget in MB for each of jobs parallel Maven
+// builds: memPerJob when set, otherwise an even share of physical memory.
+func heapPerJob(jobs, memPerJob int) int {
+	if memPerJob > 0 {
+		return memPerJob
+	}
+	return maven.HeapPerJobMB(jobs)
+}
+
+// printParallelism reports the parallel build settings when --jobs is used.
+func printParallelism(p *ui.Printer, jobs, heapMB int) {
+	if jobs < 2 {
+		return
+	}
+	msg := fmt.Sprintf("Parallel: up to %d repos at once", jobs)
+	if heapMB > 0 {
+		msg += fmt.Sprintf(", MAVEN_OPTS=-Xmx%dm each", heapMB)
+	}
+	p.Info(msg)
+}
+
+// errInterrupted is returned by commands stopped by SIGINT or SIGTERM after
+// they reported what was interrupted; the process exits with status 130.
+var errInterrupted = errors.New("interrupted")
+
+// interruptContext returns a context that is cancelled by the first SIGINT
+// or SIGTERM, so that long-running operations terminate their Maven and Git
+// process groups and record the repos they were working on as interrupted.
+// The first signal restores the default handling: a second Ctrl-C exits at
+// once. Call the returned function once the operation is over.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigs:
+			signal.Stop(sigs)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sigs)
+		cancel()
+	}
+}
+
+// printInterrupted reports an interrupted run: the repos whose work was
+// stopped midway, which are recorded as interrupted, and how to resume.
+func printInterrupted(p *ui.Printer, stopped []string, resume string) {
+	p.Newline()
+	p.Warning("Interrupted")
+	if len(stopped) > 0 {
+		names := make([]string, len(stopped))
+		for i, repo := range stopped {
+			names[i] = shortName(repo)
+		}
+		p.Info(fmt.Sprintf("Marked as interrupted: %s", strings.Join(names, ", ")))
+	}
+	p.Info(fmt.Sprintf("Run '%s' to resume", resume))
+}
+
+// addFailurePolicyFlags registers the mutually exclusive --fail-fast,
+// --keep-going and --force-continue flags shared by build and publish.
+func addFailurePolicyFlags(cmd *cobra.Command, failFast, keepGoing, forceContinue *bool) {
+	cmd.Flags().BoolVar(failFast, "fail-fast", false, "Start no new repos after the first failure")
+	cmd.Flags().BoolVar(keepGoing, "keep-going", false, "Skip only the dependents of failed repos (default)")
+	cmd.Flags().BoolVar(forceContinue, "force-continue", false, "Process every repo even when a dependency failed")
+	cmd.MarkFlagsMutuallyExclusive("fail-fast", "keep-going", "force-continue")
+}
+
+// failurePolicy maps the failure policy flags to a dag.FailurePolicy;
+// --keep-going is the default.
+func failurePolicy(failFast, forceContinue bool) dag.FailurePolicy {
+	switch {
+	case failFast:
+		return dag.FailFast
+	case forceContinue:
+		return dag.ForceContinue
+	default:
+		return dag.KeepGoing
+	}
+}
+
+// printBlocked reports a repo that was skipped because a dependency failed.
+func printBlocked(repo, blockedBy string) {
+	fmt.Printf("\r\033[K  %s %-45s %s\n", ui.StyleWarning.Render("⊘"), repo,
+		ui.StyleMuted.Render("blocked by "+shortName(blockedBy)))
+}
+
+// printBlockedSummary lists the blocked repos of a run, grouped by the failed
+// repo that blocked them. result returns the repo and blocker of the i-th of
+// n results; repos that were not blocked have an empty blocker.
+func printBlockedSummary(p *ui.Printer, n int, result func(i int) (repo, blockedBy string)) {
+	var causes []string
+	byCause := make(map[string][]string)
+	for i := 0; i < n; i++ {
+		repo, cause := result(i)
+		if cause == "" {
+			continue
+		}
+		if _, ok := byCause[cause]; !ok {
+			causes = append(causes, cause)
+		}
+		byCause[cause] = append(byCause[cause], shortName(repo))
+	}
+	if len(causes) == 0 {
+		return
+	}
+	p.Newline()
+	p.Info("Blocked repositories (not processed because a dependency failed):")
+	for _, cause := range causes {
+		p.Warning(fmt.Sprintf("  %s → %s", shortName(cause), strings.Join(byCause[cause], ", ")))
+	}
+}
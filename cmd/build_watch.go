@@ -0,0 +1,229 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/build"
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+	"github.com/fireflyframework/fireflyframework-cli/internal/report"
+	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
+	"github.com/fireflyframework/fireflyframework-cli/internal/watch"
+)
+
+// watchBuild watches repos_path and rebuilds the repos with changed files
+// and their affected dependents until interrupted. A running build is
+// cancelled and restarted when further changes arrive, so the next build
+// covers both sets of changes.
+func watchBuild(p *ui.Printer, cfg *config.Config, opts build.BuildOptions) error {
+	ctx, stop := interruptContext()
+	defer stop()
+
+	w, err := watch.New(cfg.ReposPath, watch.Options{Ignore: watchIgnored})
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", cfg.ReposPath, err)
+	}
+	batches := make(chan []string)
+	go w.Run(ctx, batches)
+
+	// Rebuilds are scoped to the changed repos, with change detection.
+	opts.ForceAll = false
+	opts.UpTo = ""
+
+	p.Newline()
+	p.Info(fmt.Sprintf("Watching %s for changes (%s) — press Ctrl+C to stop", cfg.ReposPath, w.Method()))
+
+	pending := make(map[string]bool)
+	var cancel context.CancelFunc
+	var done chan error
+	for {
+		select {
+		case <-ctx.Done():
+			p.Newline()
+			p.Info("Stopped watching")
+			if cancel != nil {
+				cancel()
+				<-done
+				p.Info("The interrupted build resumes with 'flywork build'")
+			}
+			return nil
+
+		case batch, ok := <-batches:
+			if !ok {
+				batches = nil
+				continue
+			}
+			g, err := dag.Load(cfg.ReposPath)
+			if err != nil {
+				p.Warning("Dependency graph error: " + err.Error())
+				continue
+			}
+			repos := changedRepos(g, cfg.ReposPath, batch)
+			if len(repos) == 0 {
+				continue
+			}
+			for _, repo := range repos {
+				pending[repo] = true
+			}
+			if cancel != nil {
+				cancel()
+				<-done
+				p.Warning("New changes — restarting build")
+			}
+
+			targets := make([]string, 0, len(pending))
+			for repo := range pending {
+				targets = append(targets, repo)
+			}
+			sort.Strings(targets)
+			names := make([]string, len(targets))
+			for i, repo := range targets {
+				names[i] = shortName(repo)
+			}
+			p.Newline()
+			p.Info(fmt.Sprintf("[%s] Changed: %s", time.Now().Format("15:04:05"), strings.Join(names, ", ")))
+
+			var buildCtx context.Context
+			buildCtx, cancel = context.WithCancel(ctx)
+			done = make(chan error, 1)
+			runOpts := opts
+			runOpts.TargetRepos = targets
+			runOpts.Logs = startRunLog(p, "build", cfg)
+			go func() { done <- runWatchedBuild(buildCtx, p, runOpts) }()
+
+		case err := <-done:
+			cancel()
+			cancel, done = nil, nil
+			if errors.Is(err, context.Canceled) {
+				continue
+			}
+			if err != nil {
+				p.Error("Build error: " + err.Error())
+			}
+			pending = make(map[string]bool)
+			p.Info("Watching for changes...")
+		}
+	}
+}
+
+// changedRepos maps the paths of a watch batch to the repos containing
+// relevant changes.
+func changedRepos(g *dag.Graph, reposDir string, batch []string) []string {
+	for _, rel := range batch {
+		if rel == "." {
+			// The watcher lost events; consider every repo changed and let
+			// change detection narrow the build down.
+			return g.Nodes()
+		}
+	}
+	return build.MapChangedFiles(g, reposDir, batch, build.DefaultIgnoreGlobs).SortedRepos()
+}
+
+// watchIgnored reports whether a path below repos_path is not watched:
+// Maven output, Git metadata and flywork's own state in .flywork/.
+func watchIgnored(rel string, isDir bool) bool {
+	if rel == config.FireflyDir || strings.HasPrefix(rel, config.FireflyDir+"/") {
+		return true
+	}
+	for _, seg := range strings.Split(rel, "/") {
+		if seg == "target" || seg == ".git" {
+			return true
+		}
+	}
+	// Editor swap and backup files.
+	base := path.Base(rel)
+	return !isDir && (strings.HasSuffix(base, "~") || strings.HasSuffix(base, ".swp") || strings.HasPrefix(base, ".#"))
+}
+
+// runWatchedBuild runs one rebuild of watch mode with compact output: a
+// spinner line per repo and a one-line summary. The --report file, if any,
+// is rewritten after each rebuild. It returns the context's error when the
+// build was cancelled.
+func runWatchedBuild(ctx context.Context, p *ui.Printer, opts build.BuildOptions) error {
+	started := time.Now()
+	spinner := ui.NewJobSpinner("Building")
+	opts.OnOutput = liveTail(spinner)
+	built, cached, failed, blocked := 0, 0, 0, 0
+	var g *dag.Graph
+	if buildReport != "" {
+		g, _ = dag.Load(opts.ReposDir)
+	}
+	rec := report.NewRecorder("build", opts.ReposDir, g, opts.Jobs)
+	defer opts.Logs.Finish()
+
+	results, _, err := build.RunDAGBuild(ctx, opts,
+		func(layer int, repo string, idx, total int) {
+			rec.Start(repo)
+			spinner.Add(shortName(repo))
+		},
+		func(layer int, repo string, idx, total int, r build.BuildResult) {
+			rec.Finish(buildReportRepo(layer, r))
+			if r.Interrupted {
+				return
+			}
+			spinner.Done(shortName(repo), r.Error == nil)
+			switch {
+			case r.Skipped:
+			case r.Cached:
+				cached++
+			case r.Blocked:
+				blocked++
+				printBlocked(repo, r.BlockedBy)
+			case r.Error != nil:
+				failed++
+				p.Error(fmt.Sprintf("%-45s %s", repo, failureText(r)))
+				if r.LogFile != "" {
+					p.Info(fmt.Sprintf("  Log: %s", r.LogFile))
+				}
+				if r.Diagnosis != nil {
+					for _, hint := range r.Diagnosis.Hints {
+						fmt.Printf("      %s %s\n", ui.StyleInfo.Render("→"), hint)
+					}
+				}
+			default:
+				built++
+			}
+		},
+	)
+	if err != nil {
+		spinner.Stop()
+		return err
+	}
+
+	elapsed := time.Since(started).Truncate(time.Second)
+	if len(results) == 0 {
+		p.Success("Everything is up to date — nothing to build")
+		return nil
+	}
+	msg := fmt.Sprintf("Built %d", built)
+	if cached > 0 {
+		msg += fmt.Sprintf(", %d from cache", cached)
+	}
+	if failed > 0 || blocked > 0 {
+		p.Error(fmt.Sprintf("%s, %d failed, %d blocked in %s", msg, failed, blocked, elapsed))
+	} else {
+		p.Success(fmt.Sprintf("%s in %s", msg, elapsed))
+	}
+	writeReport(p, rec, buildReport, buildReportFile)
+	return nil
+}
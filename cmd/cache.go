@@ -0,0 +1,247 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/cache"
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the build cache",
+	Long: `Commands for the build cache used by 'flywork build'. Each entry holds the
+jars and poms a repository build installed into ~/.m2, keyed by the repo's
+source fingerprint, the cache keys of its dependencies and the JDK. When a
+repo is built again with the same key, the entry is restored into ~/.m2
+instead of running Maven.
+
+Entries are stored in ~/.flywork/cache. When cache_url is configured, a
+shared HTTP cache is consulted after the local one: entries are fetched with
+GET <cache_url>/<key>.tar.gz and uploaded with PUT, sending
+FLYWORK_CACHE_TOKEN as a bearer token if set. Set build_cache to false to
+disable the cache.
+
+Available Subcommands:
+  stats      Show the size of the local cache and its hit rate
+  prune      Remove least recently used entries by age or total size
+  clear      Remove every local cache entry
+
+Examples:
+  flywork cache stats
+  flywork cache prune --max-age 30d
+  flywork cache prune --max-size 5G
+  flywork cache clear --yes`,
+}
+
+var (
+	cachePruneMaxAge  string
+	cachePruneMaxSize string
+	cacheClearYes     bool
+)
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show build cache statistics",
+	Long: `Shows the number and total size of the entries in the local cache, the
+configured backends, and the hits, misses and stores recorded by builds
+since the cache was last cleared.`,
+	RunE: runCacheStats,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old build cache entries",
+	Long: `Removes local cache entries that have not been used for longer than
+--max-age, then the least recently used entries until the cache is no
+larger than --max-size. Ages accept Go durations plus a 'd' suffix for days
+(e.g. 72h, 30d); sizes accept K, M and G suffixes (e.g. 500M, 5G). The
+shared HTTP cache is not pruned.`,
+	RunE: runCachePrune,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every build cache entry",
+	Long: `Removes every entry of the local cache and resets its statistics. The
+shared HTTP cache is not touched. Asks for confirmation unless --yes is
+given.`,
+	RunE: runCacheClear,
+}
+
+func init() {
+	cachePruneCmd.Flags().StringVar(&cachePruneMaxAge, "max-age", "", "Remove entries unused for longer than this (e.g. 72h, 30d)")
+	cachePruneCmd.Flags().StringVar(&cachePruneMaxSize, "max-size", "", "Remove least recently used entries until the cache fits (e.g. 5G)")
+	cacheClearCmd.Flags().BoolVarP(&cacheClearYes, "yes", "y", false, "Do not ask for confirmation")
+
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	p := ui.NewPrinter()
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	entries, err := cache.NewDirBackend(cache.Dir()).List()
+	if err != nil {
+		return fmt.Errorf("failed to read cache: %w", err)
+	}
+	var size int64
+	for _, e := range entries {
+		size += e.Size
+	}
+	stats, err := cache.LoadStats()
+	if err != nil {
+		return fmt.Errorf("failed to read cache statistics: %w", err)
+	}
+
+	p.Header("Build Cache")
+	if cfg.BuildCache {
+		p.KeyValue("Status", ui.StyleSuccess.Render("enabled"))
+	} else {
+		p.KeyValue("Status", ui.StyleWarning.Render("disabled (build_cache: false)"))
+	}
+	p.KeyValue("Directory", cache.Dir())
+	if cfg.CacheURL != "" {
+		p.KeyValue("Shared cache", cfg.CacheURL)
+	}
+	p.KeyValue("Entries", strconv.Itoa(len(entries)))
+	p.KeyValue("Size", formatBytes(size))
+	if len(entries) > 0 {
+		p.KeyValue("Oldest use", entries[0].LastUse.Format(time.RFC3339))
+	}
+	p.Newline()
+
+	lookups := stats.Hits + stats.Misses
+	hitRate := "—"
+	if lookups > 0 {
+		hitRate = fmt.Sprintf("%.0f%%", 100*float64(stats.Hits)/float64(lookups))
+	}
+	p.KeyValue("Hits", strconv.Itoa(stats.Hits))
+	p.KeyValue("Misses", strconv.Itoa(stats.Misses))
+	p.KeyValue("Hit rate", hitRate)
+	p.KeyValue("Stores", strconv.Itoa(stats.Stores))
+	return nil
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	p := ui.NewPrinter()
+	if cachePruneMaxAge == "" && cachePruneMaxSize == "" {
+		return fmt.Errorf("specify --max-age, --max-size or both")
+	}
+	var maxAge time.Duration
+	if cachePruneMaxAge != "" {
+		d, err := parseAge(cachePruneMaxAge)
+		if err != nil {
+			return err
+		}
+		maxAge = d
+	}
+	var maxSize int64
+	if cachePruneMaxSize != "" {
+		n, err := parseSize(cachePruneMaxSize)
+		if err != nil {
+			return err
+		}
+		maxSize = n
+	}
+
+	removed, err := cache.NewDirBackend(cache.Dir()).Prune(maxAge, maxSize)
+	if err != nil {
+		return fmt.Errorf("failed to prune cache: %w", err)
+	}
+	var freed int64
+	for _, e := range removed {
+		freed += e.Size
+	}
+	p.Success(fmt.Sprintf("Removed %d entries, freed %s", len(removed), formatBytes(freed)))
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	p := ui.NewPrinter()
+	if !cacheClearYes && !ui.Confirm(fmt.Sprintf("Remove every entry in %s?", cache.Dir()), false) {
+		return nil
+	}
+	if err := cache.NewDirBackend(cache.Dir()).Clear(); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	p.Success("Build cache cleared")
+	return nil
+}
+
+// parseAge parses a Go duration, also accepting a number of days ("30d").
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// parseSize parses a byte count with an optional K, M or G suffix.
+func parseSize(s string) (int64, error) {
+	num := strings.TrimSuffix(strings.ToUpper(s), "B")
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(num, "K"):
+		mult = 1 << 10
+	case strings.HasSuffix(num, "M"):
+		mult = 1 << 20
+	case strings.HasSuffix(num, "G"):
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		num = num[:len(num)-1]
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * float64(mult)), nil
+}
+
+// formatBytes renders a byte count with a binary unit.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -16,6 +16,7 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
@@ -42,12 +43,26 @@ Valid configuration keys:
   parent_version     Parent POM version for archetypes (default: 26.02.03)
   cli_auto_update    Auto-check for CLI updates on launch (default: false)
   branch             Git branch to clone during setup (default: develop)
+  build_cache        Restore unchanged builds from ~/.flywork/cache (default: true)
+  cache_url          Shared HTTP build cache, read and written with GET/PUT (default: none)
+  log_retention_runs Number of runs whose logs are kept in ~/.flywork/logs (default: 20)
+  log_retention_days Remove run logs older than this many days (default: 30)
+  maven_goals        Goals of Maven builds, e.g. "clean verify" (default: clean install)
+  maven_profiles     Maven profiles to activate, comma-separated (default: none)
+  maven_args         Extra arguments of every Maven invocation, e.g. "-T 1C" (default: none)
+  maven_offline      Run Maven with -o instead of -U (default: false)
+
+Maven properties (-D) and environment variables such as MAVEN_OPTS are set in
+the maven section of config.yaml (properties, env). An -Xmx in MAVEN_OPTS
+replaces the automatic heap budget of parallel builds; --mem-per-job
+replaces it in turn.
 
 Examples:
   flywork config                              Show all configuration
   flywork config get java_version             Get a single value
   flywork config set java_version 25          Set a value
   flywork config set branch main              Change the default branch
+  flywork config set maven_profiles ci        Build with -Pci
   flywork config reset                        Reset to defaults`,
 	RunE: runConfigList,
 }
@@ -59,7 +74,9 @@ var configGetCmd = &cobra.Command{
 This is useful for scripting and CI/CD integration.
 
 Valid keys: repos_path, github_org, default_group_id, java_version,
-parent_version, cli_auto_update, branch`,
+parent_version, cli_auto_update, branch, build_cache, cache_url,
+log_retention_runs, log_retention_days, maven_goals, maven_profiles,
+maven_args, maven_offline`,
 	Args:      cobra.ExactArgs(1),
 	ValidArgs: config.ValidKeys,
 	RunE:      runConfigGet,
@@ -72,9 +89,13 @@ var configSetCmd = &cobra.Command{
 ~/.flywork/config.yaml.
 
 Valid keys: repos_path, github_org, default_group_id, java_version,
-parent_version, cli_auto_update, branch
+parent_version, cli_auto_update, branch, build_cache, cache_url,
+log_retention_runs, log_retention_days, maven_goals, maven_profiles,
+maven_args, maven_offline
 
-For cli_auto_update, accepted values are: true, false, 1, 0, yes, no.`,
+For cli_auto_update, build_cache and maven_offline, accepted values are: true, false, 1, 0, yes, no.
+log_retention_runs and log_retention_days take a number; 0 disables the limit.
+maven_goals and maven_args take space-separated values; an empty value resets them.`,
 	Args:      cobra.ExactArgs(2),
 	ValidArgs: config.ValidKeys,
 	RunE:      runConfigSet,
@@ -133,6 +154,11 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	}
 
 	key, value := args[0], args[1]
+	if key == "log_retention_runs" || key == "log_retention_days" {
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("%s must be a number of 0 or more, got %q", key, value)
+		}
+	}
 	if !cfg.SetField(key, value) {
 		return fmt.Errorf("unknown key %q — valid keys: %s", key, strings.Join(config.ValidKeys, ", "))
 	}
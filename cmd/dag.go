@@ -17,8 +17,17 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/fireflyframework/fireflyframework-cli/internal/build"
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
 	"github.com/spf13/cobra"
@@ -32,18 +41,34 @@ var dagCmd = &cobra.Command{
 relationships between all framework repositories and is used by setup,
 build, and publish to determine correct processing order.
 
+Edges come from the pom.xml files of the cloned repositories, falling back
+to the embedded graph for repos that are not cloned. Additional repos, edges,
+clone URLs and build settings can be declared in ~/.flywork/dag.yaml
+(global) and <repos_path>/.flywork/dag.yaml (per workspace).
+
 Available Subcommands:
   show       Display the full dependency graph as an ASCII tree
   layers     Show repositories grouped by build layer (0 = no dependencies)
   affected   Compute transitive closure of repos affected by a change
-  export     Export the entire DAG as JSON for CI/CD consumption
+  deps       List the repositories a repository depends on
+  path       Explain why one repository depends on another
+  export     Export the DAG as JSON, DOT, Mermaid, GraphML or PlantUML
+  verify     Detect drift between the embedded graph and the workspace poms
+  lint       Find redundant edges, cycles and repos placed too deep
+  shard      Split the build plan across CI runners
 
 Examples:
   flywork dag show
   flywork dag layers
   flywork dag affected --from fireflyframework-utils
   flywork dag affected --from fireflyframework-utils --json
-  flywork dag export`,
+  flywork dag deps fireflyframework-rule-engine --transitive --layers
+  flywork dag path webhooks observability
+  flywork dag export
+  flywork dag export --format mermaid --affected-by fireflyframework-cqrs
+  flywork dag verify --json
+  flywork dag lint
+  flywork dag shard --shards 4 --index 0`,
 }
 
 var dagShowCmd = &cobra.Command{
@@ -70,6 +95,10 @@ var (
 	dagAffectedFrom   string
 	dagAffectedJSON   bool
 	dagAffectedLayers bool
+	dagAffectedFiles  []string
+	dagAffectedSince  string
+	dagAffectedIgnore []string
+	dagAffectedMatrix bool
 )
 
 var dagAffectedCmd = &cobra.Command{
@@ -80,47 +109,326 @@ the specified source repository. This is the transitive closure of the
 'depends-on' relationship — i.e., all repos that directly or indirectly
 depend on the source.
 
-The --from flag is required and must be a valid repository name from the DAG.
+The changed repositories can be given in one of three ways:
+
+  --from <repo>            A single repository name from the DAG.
+  --changed-files <paths>  Changed file paths, absolute or relative to
+                           repos_path (e.g. fireflyframework-core/pom.xml).
+                           Run from inside a cloned repository, relative
+                           paths are relative to that repository's root, as
+                           printed by 'git diff --name-only'. Use "-" to read
+                           newline-separated paths from stdin.
+  --since <ref>            Every cloned repository is diffed against the git
+                           ref; repos with changes between <ref> and HEAD are
+                           the changed ones. Repos where <ref> does not exist
+                           are treated as changed.
+
+With --changed-files and --since, files matching an --ignore glob do not
+make a repository changed (default: *.md, docs/**, .github/**), and the
+result is the union of the changed repos and all of their dependents.
+
 Use --json for machine-readable output suitable for CI/CD pipelines.
 Use --layers to group affected repos by dependency layer for ordered dispatch.
+Use --matrix to print a compact GitHub Actions matrix
+({"include":[{"repo":...,"layer":...}]}) that can be written directly to
+$GITHUB_OUTPUT.
 
 Examples:
   flywork dag affected --from fireflyframework-utils
   flywork dag affected --from fireflyframework-web --json
-  flywork dag affected --from fireflyframework-utils --layers --json`,
+  flywork dag affected --from fireflyframework-utils --layers --json
+  cd ~/.flywork/repos/fireflyframework-core && git diff --name-only HEAD~1 | flywork dag affected --changed-files - --layers
+  flywork dag affected --changed-files fireflyframework-cqrs/pom.xml,fireflyframework-eda/README.md
+  flywork dag affected --since origin/develop --ignore '*.md,docs/**,.github/**,*.txt' --json
+  echo "matrix=$(flywork dag affected --since origin/develop --matrix)" >> "$GITHUB_OUTPUT"`,
 	RunE: runDagAffected,
 }
 
-var dagExportJSON bool
+var (
+	dagDepsTransitive bool
+	dagDepsLayers     bool
+	dagDepsJSON       bool
+)
+
+var dagDepsCmd = &cobra.Command{
+	Use:   "deps <repo>",
+	Short: "List the repositories a repository depends on",
+	Long: `Lists the upstream dependencies of a repository — the opposite direction of
+'dag affected'. By default only direct dependencies are shown; --transitive
+includes everything the repository depends on directly or indirectly.
+
+Use --layers to group the dependencies by build layer, which is the order in
+which they must be built and installed before the repository itself (see
+'flywork build --up-to'). Repositories may be given with or without the
+fireflyframework- prefix.
+
+Examples:
+  flywork dag deps rule-engine
+  flywork dag deps fireflyframework-rule-engine --transitive
+  flywork dag deps rule-engine --transitive --layers --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDagDeps,
+}
+
+var (
+	dagPathJSON     bool
+	dagPathShortest bool
+)
+
+var dagPathCmd = &cobra.Command{
+	Use:   "path <from> <to>",
+	Short: "Explain why one repository depends on another",
+	Long: `Lists every dependency chain through which <from> depends on <to>. Each
+repository in a chain directly depends on the next one, so the output answers
+questions like "why does webhooks rebuild when observability changes?".
+
+Repositories may be given with or without the fireflyframework- prefix.
+Chains are listed shortest first. Use --shortest to print only one shortest
+chain and --json for machine-readable output.
+
+Examples:
+  flywork dag path webhooks observability
+  flywork dag path fireflyframework-webhooks fireflyframework-observability --shortest
+  flywork dag path webhooks observability --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDagPath,
+}
+
+var (
+	dagExportJSON       bool
+	dagExportFormat     string
+	dagExportFrom       string
+	dagExportTo         string
+	dagExportAffectedBy []string
+	dagExportStatus     bool
+)
 
 var dagExportCmd = &cobra.Command{
 	Use:   "export",
-	Short: "Export the DAG as JSON for CI/CD consumption",
-	Long: `Exports the entire dependency graph as a JSON document containing nodes,
-edges, and layer assignments. This is intended for CI/CD pipeline
-integration, allowing external tools to understand the build order and
-dependency relationships.`,
+	Short: "Export the DAG as JSON, DOT, Mermaid, GraphML or PlantUML",
+	Long: `Exports the dependency graph for CI/CD pipelines or for pasting into
+documentation. Every format clusters repositories by build layer; edges point
+from a repository to the repository it depends on.
+
+Formats (--format):
+  json       Layers and edges (default, consumed by GitHub Actions workflows)
+  dot        Graphviz digraph with one cluster per layer
+  mermaid    Mermaid flowchart with one subgraph per layer
+  graphml    GraphML with one nested graph per layer
+  plantuml   PlantUML component diagram with one package per layer
+
+The export can be restricted to a subgraph:
+  --from <repo>          The repo and everything it depends on
+  --to <repo>            The repo and everything that depends on it
+  --from A --to B        Only the repos on dependency paths from A to B
+  --affected-by <repos>  The repos and all of their transitive dependents
+
+Use --status to color nodes by their last build status from the build
+manifest (~/.flywork/build-manifest.json).
+
+Examples:
+  flywork dag export
+  flywork dag export --format dot | dot -Tsvg > dag.svg
+  flywork dag export --format mermaid --from fireflyframework-webhooks
+  flywork dag export --format plantuml --affected-by fireflyframework-cqrs,fireflyframework-eda
+  flywork dag export --format dot --status`,
 	RunE: runDagExport,
 }
 
+var (
+	dagVerifyJSON   bool
+	dagVerifyStrict bool
+)
+
+var dagVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Detect drift between the embedded graph and the workspace poms",
+	Long: `Compares the edges of the embedded framework graph with the dependencies
+actually declared in the pom.xml files of the cloned repositories and reports:
+
+  Missing edges    Declared in a pom.xml but absent from the graph. These are
+                   dangerous: the build order may be wrong. Edges that the
+                   graph still covers through a transitive path are marked
+                   as implied.
+  Extra edges      Present in the graph but not declared in any pom.xml.
+                   These only make builds slower.
+  Unknown          org.fireflyframework artifacts that cannot be attributed
+                   to any repository.
+  Untracked repos  Cloned framework repositories missing from the graph.
+  Conflicts        Artifacts declared by more than one repository; they are
+                   attributed to the first repository by name.
+  Invalid poms     Repositories whose root pom.xml cannot be parsed; the
+                   graph keeps their embedded edges.
+  Catalog mismatch Graph nodes missing from the repository catalog (or not
+                   Maven repos there) and catalog Maven repos missing from
+                   the graph.
+
+The command exits non-zero when drift is detected, so it can gate CI. Extra
+edges only fail the check with --strict.
+
+Examples:
+  flywork dag verify
+  flywork dag verify --json
+  flywork dag verify --strict`,
+	RunE: runDagVerify,
+}
+
+var (
+	dagLintJSON      bool
+	dagLintStrict    bool
+	dagLintWorkspace bool
+)
+
+var dagLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Find redundant edges, cycles and repos placed too deep",
+	Long: `Checks the dependency graph for problems that make builds slower or
+impossible:
+
+  Redundant edges  Declared edges already implied by a longer path, e.g.
+                   backoffice → eda when backoffice → core → eda. Removing
+                   them yields the transitive reduction of the graph.
+  Cycles           Every strongly connected component, not just the first
+                   cycle found.
+  Too deep         Repos that sit in a deeper layer than the pom.xml files of
+                   the cloned repositories require, because of edges that no
+                   pom declares.
+
+By default the embedded graph is linted. Use --workspace to lint the graph
+derived from the pom.xml files in the workspace instead, which helps keep the
+poms lean.
+
+Cycles always fail the command. With --strict, redundant edges and repos
+placed too deep fail it as well.
+
+Examples:
+  flywork dag lint
+  flywork dag lint --workspace
+  flywork dag lint --json --strict`,
+	RunE: runDagLint,
+}
+
+var (
+	dagShardCount int
+	dagShardIndex int
+	dagShardRepos []string
+)
+
+var dagShardCmd = &cobra.Command{
+	Use:   "shard",
+	Short: "Split the build plan across CI runners",
+	Long: `Partitions every layer of the build plan across N shards and prints the
+plan of one shard (--index) or of all shards as JSON.
+
+Repos are weighted by their last build duration from the build manifest
+(~/.flywork/build-manifest.json), so every shard of a layer gets a similar
+amount of work. Repos without a recorded duration get the average of the
+known ones; when nothing is recorded, all repos weigh the same.
+
+Each shard lists one entry per layer of the full plan, possibly empty.
+Layers are barriers: a runner may only start layer N+1 once every shard has
+finished layer N, e.g. by running one matrix job per layer with 'needs:'
+pointing at the previous layer's job.
+
+By default the whole graph is sharded. Use --repos to shard only part of it,
+e.g. the output of 'flywork dag affected'.
+
+Examples:
+  flywork dag shard --shards 4
+  flywork dag shard --shards 4 --index 2
+  flywork dag shard --shards 3 --repos fireflyframework-cqrs,fireflyframework-core,fireflyframework-web`,
+	RunE: runDagShard,
+}
+
 func init() {
-	dagAffectedCmd.Flags().StringVar(&dagAffectedFrom, "from", "", "Source repo to compute affected repos from (required)")
+	dagAffectedCmd.Flags().StringVar(&dagAffectedFrom, "from", "", "Source repo to compute affected repos from")
+	dagAffectedCmd.Flags().StringSliceVar(&dagAffectedFiles, "changed-files", nil, "Changed file paths relative to repos_path, or to the enclosing repo when run inside one (\"-\" reads stdin)")
+	dagAffectedCmd.Flags().StringVar(&dagAffectedSince, "since", "", "Git ref to diff every cloned repo against")
+	dagAffectedCmd.Flags().StringSliceVar(&dagAffectedIgnore, "ignore", build.DefaultIgnoreGlobs, "Globs of changed files to ignore")
 	dagAffectedCmd.Flags().BoolVar(&dagAffectedJSON, "json", false, "Output as JSON")
 	dagAffectedCmd.Flags().BoolVar(&dagAffectedLayers, "layers", false, "Group affected repos by dependency layer")
-	_ = dagAffectedCmd.MarkFlagRequired("from")
+	dagAffectedCmd.Flags().BoolVar(&dagAffectedMatrix, "matrix", false, "Output a compact GitHub Actions matrix")
+	dagAffectedCmd.MarkFlagsOneRequired("from", "changed-files", "since")
+	dagAffectedCmd.MarkFlagsMutuallyExclusive("from", "changed-files", "since")
+
+	dagDepsCmd.Flags().BoolVar(&dagDepsTransitive, "transitive", false, "Include indirect dependencies")
+	dagDepsCmd.Flags().BoolVar(&dagDepsLayers, "layers", false, "Group dependencies by build layer")
+	dagDepsCmd.Flags().BoolVar(&dagDepsJSON, "json", false, "Output as JSON")
 
-	dagExportCmd.Flags().BoolVar(&dagExportJSON, "json", true, "Export as JSON (default)")
+	dagPathCmd.Flags().BoolVar(&dagPathJSON, "json", false, "Output as JSON")
+	dagPathCmd.Flags().BoolVar(&dagPathShortest, "shortest", false, "Only show a shortest dependency chain")
+
+	dagExportCmd.Flags().StringVar(&dagExportFormat, "format", dag.FormatJSON, "Output format: "+strings.Join(dag.Formats, "|"))
+	dagExportCmd.Flags().StringVar(&dagExportFrom, "from", "", "Restrict to a repo and everything it depends on")
+	dagExportCmd.Flags().StringVar(&dagExportTo, "to", "", "Restrict to a repo and everything that depends on it")
+	dagExportCmd.Flags().StringSliceVar(&dagExportAffectedBy, "affected-by", nil, "Restrict to the given repos and their transitive dependents")
+	dagExportCmd.Flags().BoolVar(&dagExportStatus, "status", false, "Color nodes by last build status from the build manifest")
+	dagExportCmd.Flags().BoolVar(&dagExportJSON, "json", true, "Export as JSON")
+	_ = dagExportCmd.Flags().MarkDeprecated("json", "use --format json instead")
+
+	dagVerifyCmd.Flags().BoolVar(&dagVerifyJSON, "json", false, "Output as JSON")
+	dagVerifyCmd.Flags().BoolVar(&dagVerifyStrict, "strict", false, "Also fail on extra edges")
+
+	dagLintCmd.Flags().BoolVar(&dagLintJSON, "json", false, "Output as JSON")
+	dagLintCmd.Flags().BoolVar(&dagLintStrict, "strict", false, "Also fail on redundant edges and repos placed too deep")
+	dagLintCmd.Flags().BoolVar(&dagLintWorkspace, "workspace", false, "Lint the graph derived from the workspace poms")
+
+	dagShardCmd.Flags().IntVar(&dagShardCount, "shards", 1, "Number of shards")
+	dagShardCmd.Flags().IntVar(&dagShardIndex, "index", -1, "Print only this shard (0-based, below --shards); all shards if omitted")
+	dagShardCmd.Flags().StringSliceVar(&dagShardRepos, "repos", nil, "Only shard these repos (default: whole graph)")
+	_ = dagShardCmd.MarkFlagRequired("shards")
 
 	dagCmd.AddCommand(dagShowCmd)
 	dagCmd.AddCommand(dagLayersCmd)
 	dagCmd.AddCommand(dagAffectedCmd)
+	dagCmd.AddCommand(dagDepsCmd)
+	dagCmd.AddCommand(dagPathCmd)
 	dagCmd.AddCommand(dagExportCmd)
+	dagCmd.AddCommand(dagVerifyCmd)
+	dagCmd.AddCommand(dagLintCmd)
+	dagCmd.AddCommand(dagShardCmd)
 	rootCmd.AddCommand(dagCmd)
 }
 
+// loadGraph returns the dependency graph of the configured workspace: edges
+// from the cloned pom.xml files, the embedded graph for everything else, and
+// the dag.yaml overlays on top.
+func loadGraph() (*dag.Graph, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	g, err := dag.Load(cfg.ReposPath)
+	if err != nil {
+		return nil, fmt.Errorf("dependency graph error: %w", err)
+	}
+	return g, nil
+}
+
+// checkCatalog compares the nodes of g with the repository catalog, the
+// source of truth for which repositories exist and are built with Maven.
+func checkCatalog(g *dag.Graph) (catalog.Mismatch, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return catalog.Mismatch{}, fmt.Errorf("failed to load config: %w", err)
+	}
+	cat, err := catalog.Load(cfg.ReposPath)
+	if err != nil {
+		return catalog.Mismatch{}, fmt.Errorf("repository catalog error: %w", err)
+	}
+	return cat.Check(g), nil
+}
+
 func runDagShow(_ *cobra.Command, _ []string) error {
 	p := ui.NewPrinter()
-	g := dag.FrameworkGraph()
+	g, err := loadGraph()
+	if err != nil {
+		return err
+	}
+	mismatch, err := checkCatalog(g)
+	if err != nil {
+		return err
+	}
 
 	layers, err := g.Layers()
 	if err != nil {
@@ -154,13 +462,19 @@ func runDagShow(_ *cobra.Command, _ []string) error {
 
 	p.Newline()
 	p.Info(fmt.Sprintf("%d repositories, %d layers", g.NodeCount(), len(layers)))
+	if !mismatch.Empty() {
+		printMismatch(p, mismatch)
+	}
 
 	return nil
 }
 
 func runDagLayers(_ *cobra.Command, _ []string) error {
 	p := ui.NewPrinter()
-	g := dag.FrameworkGraph()
+	g, err := loadGraph()
+	if err != nil {
+		return err
+	}
 
 	layers, err := g.Layers()
 	if err != nil {
@@ -187,7 +501,14 @@ func runDagLayers(_ *cobra.Command, _ []string) error {
 }
 
 func runDagAffected(_ *cobra.Command, _ []string) error {
-	g := dag.FrameworkGraph()
+	g, err := loadGraph()
+	if err != nil {
+		return err
+	}
+
+	if dagAffectedFrom == "" {
+		return runDagAffectedFiles(g)
+	}
 
 	if !g.HasNode(dagAffectedFrom) {
 		return fmt.Errorf("unknown repository: %s", dagAffectedFrom)
@@ -195,6 +516,18 @@ func runDagAffected(_ *cobra.Command, _ []string) error {
 
 	affected := g.TransitiveDependentsOf(dagAffectedFrom)
 
+	if dagAffectedMatrix {
+		affectedSet := make(map[string]bool, len(affected))
+		for _, repo := range affected {
+			affectedSet[repo] = true
+		}
+		layers, err := g.Subgraph(affectedSet).Layers()
+		if err != nil {
+			return fmt.Errorf("failed to compute layers: %w", err)
+		}
+		return printMatrix(layers)
+	}
+
 	if dagAffectedJSON && dagAffectedLayers {
 		// Build a subgraph of affected repos and compute layers
 		affectedSet := make(map[string]bool, len(affected))
@@ -281,14 +614,734 @@ func runDagAffected(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+func runDagDeps(_ *cobra.Command, args []string) error {
+	g, err := loadGraph()
+	if err != nil {
+		return err
+	}
+
+	repo, err := resolveRepoArg(g, args[0])
+	if err != nil {
+		return err
+	}
+
+	var deps []string
+	if dagDepsTransitive {
+		deps = g.TransitiveDependenciesOf(repo)
+	} else {
+		deps = g.DependenciesOf(repo)
+		sort.Strings(deps)
+	}
+
+	var layers [][]string
+	if dagDepsLayers {
+		depSet := make(map[string]bool, len(deps))
+		for _, d := range deps {
+			depSet[d] = true
+		}
+		layers, err = g.Subgraph(depSet).Layers()
+		if err != nil {
+			return fmt.Errorf("failed to compute layers: %w", err)
+		}
+	}
+
+	if dagDepsJSON {
+		out := struct {
+			Repo         string     `json:"repo"`
+			Transitive   bool       `json:"transitive"`
+			Dependencies []string   `json:"dependencies"`
+			Layers       [][]string `json:"layers,omitempty"`
+			Count        int        `json:"count"`
+		}{
+			Repo:         repo,
+			Transitive:   dagDepsTransitive,
+			Dependencies: deps,
+			Layers:       layers,
+			Count:        len(deps),
+		}
+		if out.Dependencies == nil {
+			out.Dependencies = []string{}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	p := ui.NewPrinter()
+	title := "Dependencies of %s"
+	if dagDepsTransitive {
+		title = "Transitive dependencies of %s"
+	}
+	p.Header(fmt.Sprintf(title, shortName(repo)))
+	p.Newline()
+
+	if len(deps) == 0 {
+		p.Info("No upstream dependencies")
+		return nil
+	}
+
+	if dagDepsLayers {
+		for i, layer := range layers {
+			label := fmt.Sprintf("Layer %d (%d repos)", i, len(layer))
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(label))
+			for _, dep := range layer {
+				fmt.Printf("    %s %s\n", ui.StyleMuted.Render("•"), shortName(dep))
+			}
+			p.Newline()
+		}
+	} else {
+		for _, dep := range deps {
+			fmt.Printf("  %s %s\n", ui.StyleMuted.Render("•"), shortName(dep))
+		}
+		p.Newline()
+	}
+	p.Info(fmt.Sprintf("%d repos upstream of %s", len(deps), shortName(repo)))
+
+	return nil
+}
+
+func runDagPath(_ *cobra.Command, args []string) error {
+	g, err := loadGraph()
+	if err != nil {
+		return err
+	}
+
+	from, err := resolveRepoArg(g, args[0])
+	if err != nil {
+		return err
+	}
+	to, err := resolveRepoArg(g, args[1])
+	if err != nil {
+		return err
+	}
+
+	shortest := g.ShortestPath(from, to)
+	var paths [][]string
+	if dagPathShortest {
+		if shortest != nil {
+			paths = [][]string{shortest}
+		}
+	} else {
+		paths = g.AllPaths(from, to)
+	}
+
+	if dagPathJSON {
+		if paths == nil {
+			paths = [][]string{}
+		}
+		out := struct {
+			From     string     `json:"from"`
+			To       string     `json:"to"`
+			Depends  bool       `json:"depends"`
+			Shortest []string   `json:"shortest"`
+			Paths    [][]string `json:"paths"`
+			Count    int        `json:"count"`
+		}{
+			From:     from,
+			To:       to,
+			Depends:  shortest != nil,
+			Shortest: shortest,
+			Paths:    paths,
+			Count:    len(paths),
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	p := ui.NewPrinter()
+	p.Header(fmt.Sprintf("Why %s depends on %s", shortName(from), shortName(to)))
+	p.Newline()
+
+	if shortest == nil {
+		p.Info(fmt.Sprintf("%s does not depend on %s", shortName(from), shortName(to)))
+		if g.ShortestPath(to, from) != nil {
+			p.Info(fmt.Sprintf("%s depends on %s — try: flywork dag path %s %s",
+				shortName(to), shortName(from), shortName(to), shortName(from)))
+		}
+		return nil
+	}
+
+	arrow := ui.StyleMuted.Render(" → ")
+	for _, path := range paths {
+		names := make([]string, len(path))
+		for i, repo := range path {
+			names[i] = shortName(repo)
+		}
+		names[0] = ui.StyleBold.Render(names[0])
+		names[len(names)-1] = ui.StylePrimary.Render(names[len(names)-1])
+		fmt.Printf("  %s\n", strings.Join(names, arrow))
+	}
+
+	hops := fmt.Sprintf("%d hops", len(shortest)-1)
+	if len(shortest) == 2 {
+		hops = "direct dependency"
+	}
+	p.Newline()
+	if dagPathShortest {
+		p.Info("Shortest chain: " + hops)
+	} else {
+		p.Info(fmt.Sprintf("%d dependency chains (shortest: %s)", len(paths), hops))
+	}
+
+	return nil
+}
+
+// resolveRepoArg accepts a repository name with or without the
+// fireflyframework- prefix and returns the full node name.
+func resolveRepoArg(g *dag.Graph, name string) (string, error) {
+	if g.HasNode(name) {
+		return name, nil
+	}
+	if full := "fireflyframework-" + name; g.HasNode(full) {
+		return full, nil
+	}
+	return "", fmt.Errorf("unknown repository: %s", name)
+}
+
+// matrixEntry is one job of a GitHub Actions matrix.
+type matrixEntry struct {
+	Repo  string `json:"repo"`
+	Layer int    `json:"layer"`
+}
+
+type ciMatrix struct {
+	Include []matrixEntry `json:"include"`
+}
+
+func newMatrix(layers [][]string) ciMatrix {
+	m := ciMatrix{Include: []matrixEntry{}}
+	for i, layer := range layers {
+		for _, repo := range layer {
+			m.Include = append(m.Include, matrixEntry{Repo: repo, Layer: i})
+		}
+	}
+	return m
+}
+
+// printMatrix prints a single-line matrix suitable for $GITHUB_OUTPUT.
+func printMatrix(layers [][]string) error {
+	data, err := json.Marshal(newMatrix(layers))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// inEnclosingRepo makes relative paths absolute against the root of the
+// cloned repo containing the working directory, if any: git prints paths
+// relative to the repository root, not to repos_path.
+func inEnclosingRepo(g *dag.Graph, reposDir string, files []string) []string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return files
+	}
+	root, err := filepath.Abs(reposDir)
+	if err != nil {
+		return files
+	}
+	rel, err := filepath.Rel(root, cwd)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return files
+	}
+	repo, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	if !g.HasNode(repo) {
+		return files
+	}
+	out := make([]string, len(files))
+	for i, f := range files {
+		if f = strings.TrimSpace(f); f != "" && !filepath.IsAbs(f) {
+			f = filepath.Join(root, repo, f)
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// readChangedFiles expands the --changed-files values: "-" reads paths from
+// stdin, one per line.
+func readChangedFiles(values []string) ([]string, error) {
+	var files []string
+	for _, v := range values {
+		if v != "-" {
+			files = append(files, v)
+			continue
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changed files from stdin: %w", err)
+		}
+		files = append(files, strings.Split(string(data), "\n")...)
+	}
+	return files, nil
+}
+
+func runDagAffectedFiles(g *dag.Graph) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var fc *build.FileChanges
+	if dagAffectedSince != "" {
+		fc = build.ChangedFilesSince(g, cfg.ReposPath, dagAffectedSince, dagAffectedIgnore)
+	} else {
+		files, err := readChangedFiles(dagAffectedFiles)
+		if err != nil {
+			return err
+		}
+		fc = build.MapChangedFiles(g, cfg.ReposPath, inEnclosingRepo(g, cfg.ReposPath, files), dagAffectedIgnore)
+	}
+
+	changed := fc.SortedRepos()
+	affectedSet := build.TransitiveClosure(g, fc.Changed())
+	layers, err := g.Subgraph(affectedSet).Layers()
+	if err != nil {
+		return fmt.Errorf("failed to compute layers: %w", err)
+	}
+	var affected []string
+	for _, layer := range layers {
+		affected = append(affected, layer...)
+	}
+	sort.Strings(affected)
+
+	if dagAffectedMatrix {
+		return printMatrix(layers)
+	}
+
+	if dagAffectedJSON {
+		out := struct {
+			Changed   []string          `json:"changed"`
+			Affected  []string          `json:"affected"`
+			Layers    [][]string        `json:"layers"`
+			Count     int               `json:"count"`
+			Ignored   []string          `json:"ignored_files"`
+			Unmatched []string          `json:"unmatched_files"`
+			Errors    map[string]string `json:"errors,omitempty"`
+			Matrix    ciMatrix          `json:"matrix"`
+		}{
+			Changed:   changed,
+			Affected:  affected,
+			Layers:    layers,
+			Count:     len(affected),
+			Ignored:   fc.Ignored,
+			Unmatched: fc.Unmatched,
+			Errors:    fc.Errors,
+			Matrix:    newMatrix(layers),
+		}
+		if out.Affected == nil {
+			out.Affected = []string{}
+		}
+		if out.Layers == nil {
+			out.Layers = [][]string{}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	p := ui.NewPrinter()
+	if dagAffectedSince != "" {
+		p.Header(fmt.Sprintf("Affected since %s", dagAffectedSince))
+	} else {
+		p.Header("Affected by changed files")
+	}
+	p.Newline()
+
+	for _, repo := range changed {
+		if reason, ok := fc.Errors[repo]; ok {
+			p.Warning(fmt.Sprintf("%s treated as changed: %s", shortName(repo), reason))
+		}
+	}
+	if len(fc.Unmatched) > 0 {
+		p.Warning(fmt.Sprintf("%d files outside any known repository", len(fc.Unmatched)))
+		if verbose {
+			for _, f := range fc.Unmatched {
+				fmt.Printf("    %s %s\n", ui.StyleMuted.Render("•"), f)
+			}
+		}
+	}
+	if len(fc.Ignored) > 0 {
+		p.Info(fmt.Sprintf("%d files ignored (%s)", len(fc.Ignored), strings.Join(dagAffectedIgnore, ", ")))
+	}
+
+	if len(changed) == 0 {
+		p.Info("No repositories changed")
+		return nil
+	}
+
+	fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Changed (%d repos)", len(changed))))
+	for _, repo := range changed {
+		files := fmt.Sprintf("%d files", len(fc.Repos[repo]))
+		fmt.Printf("    %s %s %s\n", ui.StyleMuted.Render("•"), shortName(repo), ui.StyleMuted.Render(files))
+	}
+	p.Newline()
+
+	if dagAffectedLayers {
+		for i, layer := range layers {
+			label := fmt.Sprintf("Layer %d (%d repos)", i, len(layer))
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(label))
+			for _, repo := range layer {
+				fmt.Printf("    %s %s\n", ui.StyleMuted.Render("•"), shortName(repo))
+			}
+			p.Newline()
+		}
+	} else {
+		fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Affected (%d repos)", len(affected))))
+		for _, repo := range affected {
+			fmt.Printf("    %s %s\n", ui.StyleMuted.Render("•"), shortName(repo))
+		}
+		p.Newline()
+	}
+	p.Info(fmt.Sprintf("%d repos changed, %d affected across %d layers", len(changed), len(affected), len(layers)))
+
+	return nil
+}
+
 func runDagExport(_ *cobra.Command, _ []string) error {
-	g := dag.FrameworkGraph()
+	g, err := loadGraph()
+	if err != nil {
+		return err
+	}
+	g, err = g.Restrict(dagExportFrom, dagExportTo, dagExportAffectedBy)
+	if err != nil {
+		return err
+	}
+
+	opts := dag.ExportOptions{Format: dagExportFormat}
+	if dagExportStatus {
+		manifest, err := build.LoadManifest(build.DefaultManifestPath())
+		if err != nil {
+			return fmt.Errorf("failed to load build manifest: %w", err)
+		}
+		opts.Status = make(map[string]string)
+		if manifest != nil {
+			for repo, bs := range manifest.Repos {
+				opts.Status[repo] = bs.Status
+			}
+		}
+	}
+
+	data, err := g.Export(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(data))
+	if opts.Format == dag.FormatJSON {
+		fmt.Println()
+	}
+	return nil
+}
+
+func runDagVerify(_ *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	ws, err := dag.ScanWorkspace(cfg.ReposPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to scan workspace: %w", err)
+	}
+	if ws == nil || len(ws.Repos) == 0 {
+		return fmt.Errorf("no framework repositories found in %s — run 'flywork setup' first", cfg.ReposPath)
+	}
 
-	data, err := g.ExportJSON()
+	expected, err := dag.Embedded(cfg.ReposPath)
+	if err != nil {
+		return err
+	}
+	drift := dag.Verify(expected, ws)
+	mismatch, err := checkCatalog(expected)
 	if err != nil {
 		return err
 	}
+	failed := drift.HasDrift() || !mismatch.Empty() || (dagVerifyStrict && len(drift.Extra) > 0)
+
+	dangerous := 0
+	for _, m := range drift.Missing {
+		if !m.Implied {
+			dangerous++
+		}
+	}
+	unknown := 0
+	for _, arts := range drift.Unknown {
+		unknown += len(arts)
+	}
+
+	if dagVerifyJSON {
+		out := struct {
+			*dag.Drift
+			Catalog catalog.Mismatch `json:"catalog_mismatch"`
+		}{drift, mismatch}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		p := ui.NewPrinter()
+		p.Header("Graph Verification")
+		p.Newline()
+		p.Info(fmt.Sprintf("Checked %d cloned repositories against the embedded graph", drift.Checked))
 
+		if len(drift.Missing) > 0 {
+			p.Newline()
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Missing edges (%d)", len(drift.Missing))))
+			for _, m := range drift.Missing {
+				line := fmt.Sprintf("%s → %s", shortName(m.From), shortName(m.To))
+				if m.Implied {
+					p.Warning(line + ui.StyleMuted.Render("  (implied transitively)"))
+				} else {
+					p.Error(line)
+				}
+			}
+		}
+
+		if len(drift.Extra) > 0 {
+			p.Newline()
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Extra edges (%d)", len(drift.Extra))))
+			for _, e := range drift.Extra {
+				p.Warning(fmt.Sprintf("%s → %s", shortName(e.From), shortName(e.To)))
+			}
+		}
+
+		if unknown > 0 {
+			p.Newline()
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Unknown framework artifacts (%d)", unknown)))
+			for _, repo := range ws.Repos {
+				for _, art := range drift.Unknown[repo] {
+					p.Error(fmt.Sprintf("%s references %s", shortName(repo), art))
+				}
+			}
+		}
+
+		if len(drift.Untracked) > 0 {
+			p.Newline()
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Untracked repositories (%d)", len(drift.Untracked))))
+			for _, repo := range drift.Untracked {
+				p.Error(shortName(repo))
+			}
+		}
+
+		if len(drift.Conflicts) > 0 {
+			p.Newline()
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Conflicting artifacts (%d)", len(drift.Conflicts))))
+			for _, art := range slices.Sorted(maps.Keys(drift.Conflicts)) {
+				repos := drift.Conflicts[art]
+				p.Error(fmt.Sprintf("%s declared by %s", art, strings.Join(repos, ", ")) +
+					ui.StyleMuted.Render("  (attributed to "+shortName(repos[0])+")"))
+			}
+		}
+
+		if len(drift.Invalid) > 0 {
+			p.Newline()
+			fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Invalid poms (%d)", len(drift.Invalid))))
+			for _, repo := range slices.Sorted(maps.Keys(drift.Invalid)) {
+				p.Error(fmt.Sprintf("%s: %s", shortName(repo), drift.Invalid[repo]))
+			}
+		}
+
+		if !mismatch.Empty() {
+			p.Newline()
+			fmt.Printf("  %s\n", ui.StylePrimary.Render("Catalog mismatch"))
+			printMismatch(p, mismatch)
+		}
+
+		p.Newline()
+		if !failed {
+			p.Success("Embedded graph matches the workspace poms")
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("graph drift detected: %d missing edges (%d dangerous), %d extra, %d unknown artifacts, %d untracked repos, %d conflicting artifacts, %d invalid poms, %d catalog mismatches",
+			len(drift.Missing), dangerous, len(drift.Extra), unknown, len(drift.Untracked), len(drift.Conflicts), len(drift.Invalid),
+			len(mismatch.NotInGraph)+len(mismatch.NotInCatalog)+len(mismatch.NotMaven))
+	}
+	return nil
+}
+
+// shortName strips the common fireflyframework- prefix for display.
+func shortName(repo string) string {
+	return strings.TrimPrefix(repo, "fireflyframework-")
+}
+
+func runDagLint(_ *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	ws, err := dag.ScanWorkspace(cfg.ReposPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to scan workspace: %w", err)
+	}
+
+	g, err := dag.Embedded(cfg.ReposPath)
+	if err != nil {
+		return err
+	}
+	source := "embedded graph"
+	if dagLintWorkspace {
+		if ws == nil || len(ws.Repos) == 0 {
+			return fmt.Errorf("no framework repositories found in %s — run 'flywork setup' first", cfg.ReposPath)
+		}
+		g = ws.Graph()
+		source = "workspace poms"
+		// The depth check compares against the poms, which is a no-op here.
+		ws = nil
+	}
+
+	report := dag.Lint(g, ws)
+
+	if dagLintJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printLintReport(report, source)
+	}
+
+	switch {
+	case len(report.Cycles) > 0:
+		return fmt.Errorf("dependency graph has %d cycle(s)", len(report.Cycles))
+	case dagLintStrict && !report.Clean():
+		return fmt.Errorf("dependency graph lint failed: %d redundant edge(s), %d repo(s) placed too deep",
+			len(report.Redundant), len(report.Deep))
+	}
+	return nil
+}
+
+func printLintReport(report *dag.LintReport, source string) {
+	p := ui.NewPrinter()
+	p.Header("Graph Lint")
+	p.Newline()
+	p.Info(fmt.Sprintf("Linted the %s: %d repositories, %d edges", source, report.Nodes, report.Edges))
+
+	if len(report.Cycles) > 0 {
+		p.Newline()
+		fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Cycles (%d)", len(report.Cycles))))
+		for _, scc := range report.Cycles {
+			names := make([]string, len(scc))
+			for i, repo := range scc {
+				names[i] = shortName(repo)
+			}
+			p.Error(strings.Join(names, ", "))
+		}
+		p.Newline()
+		p.Info("Redundancy and depth checks skipped until the cycles are resolved")
+		return
+	}
+
+	if len(report.Redundant) > 0 {
+		p.Newline()
+		fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Redundant edges (%d)", len(report.Redundant))))
+		for _, r := range report.Redundant {
+			via := make([]string, len(r.Via))
+			for i, repo := range r.Via {
+				via[i] = shortName(repo)
+			}
+			p.Warning(fmt.Sprintf("%s → %s", shortName(r.From), shortName(r.To)) +
+				ui.StyleMuted.Render("  (via "+strings.Join(via, " → ")+")"))
+		}
+	}
+
+	if len(report.Deep) > 0 {
+		p.Newline()
+		fmt.Printf("  %s\n", ui.StylePrimary.Render(fmt.Sprintf("Deeper than necessary (%d)", len(report.Deep))))
+		for _, d := range report.Deep {
+			reason := "pushed down by an upstream repo"
+			if len(d.Extra) > 0 {
+				names := make([]string, len(d.Extra))
+				for i, repo := range d.Extra {
+					names[i] = shortName(repo)
+				}
+				reason = "undeclared edges to " + strings.Join(names, ", ")
+			}
+			p.Warning(fmt.Sprintf("%s in layer %d, could be layer %d", shortName(d.Repo), d.Layer, d.MinLayer) +
+				ui.StyleMuted.Render("  ("+reason+")"))
+		}
+	}
+
+	p.Newline()
+	if !report.DepthChecked && source != "workspace poms" {
+		p.Info("Depth check skipped: no cloned framework repositories to compare against")
+	}
+	if report.Clean() {
+		p.Success("Graph is lean: no redundant edges, cycles or repos placed too deep")
+	} else {
+		p.Info(fmt.Sprintf("Transitive reduction: %d of %d edges are necessary", report.ReducedEdges, report.Edges))
+	}
+}
+
+func runDagShard(cmd *cobra.Command, _ []string) error {
+	// A mistyped index must not print every shard and have each CI runner
+	// build the whole plan.
+	selectShard := cmd.Flags().Changed("index")
+	if selectShard && (dagShardIndex < 0 || dagShardIndex >= dagShardCount) {
+		return fmt.Errorf("--index %d is out of range for %d shards", dagShardIndex, dagShardCount)
+	}
+
+	g, err := loadGraph()
+	if err != nil {
+		return err
+	}
+
+	if len(dagShardRepos) > 0 {
+		selected := make(map[string]bool, len(dagShardRepos))
+		for _, name := range dagShardRepos {
+			repo, err := resolveRepoArg(g, name)
+			if err != nil {
+				return err
+			}
+			selected[repo] = true
+		}
+		g = g.Subgraph(selected)
+	}
+
+	layers, err := g.Layers()
+	if err != nil {
+		return err
+	}
+
+	weights := make(map[string]float64)
+	manifest, err := build.LoadManifest(build.DefaultManifestPath())
+	if err != nil {
+		return fmt.Errorf("failed to load build manifest: %w", err)
+	}
+	if manifest != nil {
+		for repo, d := range manifest.Durations() {
+			weights[repo] = d.Seconds()
+		}
+	}
+
+	shards, err := dag.ShardLayers(layers, dagShardCount, weights)
+	if err != nil {
+		return err
+	}
+
+	var out any = shards
+	if selectShard {
+		out = shards[dagShardIndex]
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
 	fmt.Println(string(data))
 	return nil
 }
@@ -20,10 +20,11 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
-	"github.com/fireflyframework/fireflyframework-cli/internal/setup"
 	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
 	"github.com/fireflyframework/fireflyframework-cli/internal/version"
 	"github.com/spf13/cobra"
@@ -197,7 +198,8 @@ The bump process:
   1. Detects the current version from the parent POM
   2. Computes or accepts the target version
   3. Updates all pom.xml files across every cloned repository
-  4. Updates the GenAI module version files (if present)
+  4. Updates the version files the repo catalog declares for non-POM
+     modules such as the GenAI Python package (if cloned)
   5. Optionally commits changes (--commit, default: true)
   6. Optionally tags each repo with v<version> (--tag, default: true)
   7. Optionally pushes to remote (--push, default: false)
@@ -281,7 +283,16 @@ func runFwversionBump(cmd *cobra.Command, args []string) error {
 	// ── Phase 4: POM updates ────────────────────────────────────────────
 	p.StageHeader(2, "Updating POM Files")
 
-	totalRepos := len(setup.FrameworkRepos)
+	g, err := dag.LoadFramework(cfg.ReposPath)
+	if err != nil {
+		return fmt.Errorf("dependency graph error: %w", err)
+	}
+	order, err := g.FlatOrder()
+	if err != nil {
+		return fmt.Errorf("dependency graph error: %w", err)
+	}
+
+	totalRepos := len(order)
 	pomBar := ui.NewProgressBar(totalRepos, "repos")
 	totalFiles := 0
 	totalUpdated := 0
@@ -314,16 +325,27 @@ func runFwversionBump(cmd *cobra.Command, args []string) error {
 	p.Newline()
 	p.Info(fmt.Sprintf("POM files: %d found, %d updated across %d repos", totalFiles, totalUpdated, totalRepos))
 
-	// ── Phase 5: GenAI update ───────────────────────────────────────────
-	genaiDir := filepath.Join(cfg.ReposPath, "fireflyframework-genai")
-	if _, err := os.Stat(genaiDir); err == nil {
+	// ── Phase 5: Catalog version files ──────────────────────────────────
+	cat, err := catalog.Load(cfg.ReposPath)
+	if err != nil {
+		return fmt.Errorf("repository catalog error: %w", err)
+	}
+	for _, entry := range cat.Repos {
+		if len(entry.VersionFiles) == 0 {
+			continue
+		}
+		repoDir := filepath.Join(cfg.ReposPath, entry.Name)
+		if _, err := os.Stat(repoDir); err != nil {
+			continue
+		}
 		p.Newline()
-		spinner := ui.NewSpinner("Updating GenAI module...")
+		short := shortName(entry.Name)
+		spinner := ui.NewSpinner(fmt.Sprintf("Updating %s version files...", short))
 		spinner.Start()
-		genaiErr := version.BumpGenAI(genaiDir, oldVer, newVer, bumpDryRun)
-		spinner.Stop(genaiErr == nil)
-		if genaiErr != nil {
-			p.Warning("GenAI update: " + genaiErr.Error())
+		fileErr := version.BumpVersionFiles(repoDir, entry.VersionFiles, oldVer, newVer, bumpDryRun)
+		spinner.Stop(fileErr == nil)
+		if fileErr != nil {
+			p.Warning(fmt.Sprintf("%s update: %s", short, fileErr.Error()))
 		}
 	}
 
@@ -333,7 +355,7 @@ func runFwversionBump(cmd *cobra.Command, args []string) error {
 		installBar := ui.NewProgressBar(totalRepos, "installed")
 		installFailed := 0
 
-		for _, repo := range setup.FrameworkRepos {
+		for _, repo := range order {
 			repoDir := filepath.Join(cfg.ReposPath, repo)
 			pomPath := filepath.Join(repoDir, "pom.xml")
 			if _, err := os.Stat(pomPath); os.IsNotExist(err) {
@@ -0,0 +1,500 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/runlog"
+	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Inspect the Maven logs of past runs",
+	Long: `Commands for the Maven logs kept by 'flywork build', 'setup', 'update' and
+'publish'. Every run gets its own directory under ~/.flywork/logs, named
+after its start time and command (e.g. 20260316-142501-build), with one log
+file per repository, successful or not, and a run.json index. Maven output
+is written to the log as it is printed; a log larger than 64 MB is rotated
+to <repo>.log.1 and <repo>.log.2, which show and grep include.
+
+Old runs are removed when a new one starts: only the most recent
+log_retention_runs runs (default 20) started within the last
+log_retention_days days (default 30) are kept. Set either to 0 to disable
+that limit.
+
+Run IDs may be abbreviated to any unique prefix. Repository names may omit
+the fireflyframework- prefix.
+
+Available Subcommands:
+  list       List the logged runs, or the runs that logged a repository
+  show       Print the log of a repository
+  tail       Print the end of the logs of a run, optionally following it
+  grep       Search the logs of a run, or of every run
+
+Examples:
+  flywork logs list
+  flywork logs list core
+  flywork logs show core
+  flywork logs show core --run 20260316-1425
+  flywork logs tail -f
+  flywork logs grep "BUILD FAILURE" --all`,
+}
+
+var (
+	logsRun        string
+	logsTailLines  int
+	logsTailFollow bool
+	logsGrepAll    bool
+	logsGrepIgnore bool
+	logsGrepRepo   string
+)
+
+var logsListCmd = &cobra.Command{
+	Use:   "list [repo]",
+	Short: "List the logged runs",
+	Long: `Lists the logged runs, newest first, with their command, start time,
+duration and the number of repositories that succeeded, failed or were
+interrupted. Given a repository, lists the runs that logged it with its
+status in each, to compare runs.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogsList,
+}
+
+var logsShowCmd = &cobra.Command{
+	Use:   "show <repo>",
+	Short: "Print the log of a repository",
+	Long: `Prints the Maven log of a repository from the latest run that built it, or
+from the run given with --run.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogsShow,
+}
+
+var logsTailCmd = &cobra.Command{
+	Use:   "tail [repo]",
+	Short: "Print the end of the logs of a run",
+	Long: `Prints the last lines of every log of the latest run, or of the run given
+with --run, each prefixed with its repository. Given a repository, only its
+log is printed.
+
+With --follow, new output is printed as it is written until the run
+finishes or Ctrl+C is pressed, so a build running in another terminal can
+be watched.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogsTail,
+}
+
+var logsGrepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search the logs of a run",
+	Long: `Prints the log lines matching a regular expression, with their repository
+and line number. Searches the latest run, the run given with --run, or
+every run with --all.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogsGrep,
+}
+
+func init() {
+	logsShowCmd.Flags().StringVar(&logsRun, "run", "", "Run ID (default: latest run with a log for the repo)")
+	logsTailCmd.Flags().StringVar(&logsRun, "run", "", "Run ID (default: latest run)")
+	logsTailCmd.Flags().IntVarP(&logsTailLines, "lines", "n", 10, "Number of lines to print per log")
+	logsTailCmd.Flags().BoolVarP(&logsTailFollow, "follow", "f", false, "Keep printing new output until the run finishes")
+	logsGrepCmd.Flags().StringVar(&logsRun, "run", "", "Run ID (default: latest run)")
+	logsGrepCmd.Flags().BoolVar(&logsGrepAll, "all", false, "Search every logged run")
+	logsGrepCmd.Flags().BoolVarP(&logsGrepIgnore, "ignore-case", "i", false, "Match case-insensitively")
+	logsGrepCmd.Flags().StringVar(&logsGrepRepo, "repo", "", "Only search the log of this repository")
+
+	logsCmd.AddCommand(logsListCmd)
+	logsCmd.AddCommand(logsShowCmd)
+	logsCmd.AddCommand(logsTailCmd)
+	logsCmd.AddCommand(logsGrepCmd)
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogsList(cmd *cobra.Command, args []string) error {
+	p := ui.NewPrinter()
+	runs, err := runlog.List()
+	if err != nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	if len(args) == 1 {
+		p.Header("Logs of " + args[0])
+		p.Newline()
+		found := 0
+		for _, run := range runs {
+			e, ok := logEntry(run, args[0])
+			if !ok {
+				continue
+			}
+			found++
+			fmt.Printf("  %s %-28s %-8s %s  %s\n", statusMark(e.Status), run.ID, run.Command,
+				e.Time.Local().Format("2006-01-02 15:04:05"), ui.StyleMuted.Render(e.Error))
+		}
+		p.Newline()
+		if found == 0 {
+			p.Info(fmt.Sprintf("No logs for %s in %s", args[0], runlog.Dir()))
+			return nil
+		}
+		p.Info(fmt.Sprintf("%d runs logged %s", found, args[0]))
+		return nil
+	}
+
+	p.Header("Logged Runs")
+	p.Newline()
+	for _, run := range runs {
+		counts := run.Counts()
+		status := runlog.StatusSuccess
+		switch {
+		case counts[runlog.StatusFailed] > 0:
+			status = runlog.StatusFailed
+		case counts[runlog.StatusInterrupted] > 0 || run.FinishedAt.IsZero():
+			status = runlog.StatusInterrupted
+		}
+		duration := "unfinished"
+		if !run.FinishedAt.IsZero() {
+			duration = run.FinishedAt.Sub(run.StartedAt).Truncate(time.Second).String()
+		}
+		parts := []string{fmt.Sprintf("%d ok", counts[runlog.StatusSuccess])}
+		if n := counts[runlog.StatusFailed]; n > 0 {
+			parts = append(parts, ui.StyleError.Render(fmt.Sprintf("%d failed", n)))
+		}
+		if n := counts[runlog.StatusInterrupted]; n > 0 {
+			parts = append(parts, ui.StyleWarning.Render(fmt.Sprintf("%d interrupted", n)))
+		}
+		fmt.Printf("  %s %-28s %-8s %s  %-8s %s\n", statusMark(status), run.ID, run.Command,
+			run.StartedAt.Local().Format("2006-01-02 15:04:05"), duration, strings.Join(parts, ", "))
+	}
+	p.Newline()
+	p.Info(fmt.Sprintf("%d runs in %s", len(runs), runlog.Dir()))
+	return nil
+}
+
+func runLogsShow(cmd *cobra.Command, args []string) error {
+	run, e, err := findRepoLog(logsRun, args[0])
+	if err != nil {
+		return err
+	}
+	segments := maven.LogSegments(filepath.Join(run.Dir(), e.File))
+	if len(segments) == 0 {
+		return fmt.Errorf("log of %s in run %s is missing", e.Repo, run.ID)
+	}
+	for _, path := range segments {
+		if err := printFile(path); err != nil {
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+	}
+	return nil
+}
+
+// printFile copies a file to stdout.
+func printFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(os.Stdout, f)
+	return err
+}
+
+func runLogsTail(cmd *cobra.Command, args []string) error {
+	var run *runlog.Run
+	var repo string
+	if len(args) == 1 {
+		r, e, err := findRepoLog(logsRun, args[0])
+		if err != nil {
+			return err
+		}
+		run, repo = r, e.Repo
+	} else {
+		r, err := runlog.Find(logsRun)
+		if err != nil {
+			return err
+		}
+		run = r
+	}
+
+	files := logFiles(run, repo)
+	width := 0
+	for _, path := range files {
+		width = max(width, len(logRepo(path)))
+	}
+	offsets := make(map[string]int64, len(files))
+	for _, path := range files {
+		lines, size, err := lastLines(path, logsTailLines)
+		if err != nil {
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+		for _, line := range lines {
+			printLogLine(logRepo(path), width, line)
+		}
+		offsets[path] = size
+	}
+	if !logsTailFollow {
+		return nil
+	}
+
+	// Follow by polling: the logs are plain files written by another
+	// process, possibly one that has not started every repo yet.
+	ctx, stop := interruptContext()
+	defer stop()
+	partial := make(map[string][]byte)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		finished := true
+		if latest, err := runlog.Load(run.ID); err == nil {
+			finished = !latest.FinishedAt.IsZero()
+		}
+		for _, path := range logFiles(run, repo) {
+			data, offset, err := readFrom(path, offsets[path])
+			if err != nil {
+				continue
+			}
+			offsets[path] = offset
+			data = append(partial[path], data...)
+			i := bytes.LastIndexByte(data, '\n')
+			if i < 0 {
+				partial[path] = data
+				continue
+			}
+			width = max(width, len(logRepo(path)))
+			for _, line := range strings.Split(string(data[:i]), "\n") {
+				printLogLine(logRepo(path), width, line)
+			}
+			partial[path] = data[i+1:]
+		}
+		if finished {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func runLogsGrep(cmd *cobra.Command, args []string) error {
+	pattern := args[0]
+	if logsGrepIgnore {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	var runs []*runlog.Run
+	if logsGrepAll {
+		if runs, err = runlog.List(); err != nil {
+			return fmt.Errorf("failed to read logs: %w", err)
+		}
+	} else {
+		run, err := runlog.Find(logsRun)
+		if err != nil {
+			return err
+		}
+		runs = []*runlog.Run{run}
+	}
+
+	matches := 0
+	for _, run := range runs {
+		repo := ""
+		if logsGrepRepo != "" {
+			e, ok := logEntry(run, logsGrepRepo)
+			if !ok {
+				continue
+			}
+			repo = e.Repo
+		}
+		for _, path := range logFiles(run, repo) {
+			// Rotated segments are searched too, e.g. as core.1.
+			for _, segment := range maven.LogSegments(path) {
+				prefix := logRepo(path) + strings.TrimPrefix(segment, path)
+				if logsGrepAll {
+					prefix = run.ID + "/" + prefix
+				}
+				n, err := grepFile(segment, re, prefix)
+				if err != nil {
+					return fmt.Errorf("failed to read log: %w", err)
+				}
+				matches += n
+			}
+		}
+	}
+	if matches == 0 {
+		return fmt.Errorf("no matches for %q", args[0])
+	}
+	return nil
+}
+
+// findRepoLog returns the log entry of a repository in the run with the
+// given ID, or in the latest run that logged it when id is empty.
+func findRepoLog(id, name string) (*runlog.Run, runlog.Entry, error) {
+	if id != "" {
+		run, err := runlog.Find(id)
+		if err != nil {
+			return nil, runlog.Entry{}, err
+		}
+		e, ok := logEntry(run, name)
+		if !ok {
+			return nil, runlog.Entry{}, fmt.Errorf("run %s has no log for %s", run.ID, name)
+		}
+		return run, e, nil
+	}
+	runs, err := runlog.List()
+	if err != nil {
+		return nil, runlog.Entry{}, fmt.Errorf("failed to read logs: %w", err)
+	}
+	for _, run := range runs {
+		if e, ok := logEntry(run, name); ok {
+			return run, e, nil
+		}
+	}
+	return nil, runlog.Entry{}, fmt.Errorf("no logs for %s in %s", name, runlog.Dir())
+}
+
+// logEntry returns the log entry of a repository in run, accepting names
+// without the fireflyframework- prefix.
+func logEntry(run *runlog.Run, name string) (runlog.Entry, bool) {
+	if e, ok := run.Entry(name); ok {
+		return e, true
+	}
+	return run.Entry("fireflyframework-" + name)
+}
+
+// logFiles returns the log files of run, or only that of repo when set.
+// Files not yet in the run index are included, so that logs still being
+// written are followed too.
+func logFiles(run *runlog.Run, repo string) []string {
+	if repo != "" {
+		return []string{run.Path(repo)}
+	}
+	files, _ := filepath.Glob(filepath.Join(run.Dir(), "*.log"))
+	sort.Strings(files)
+	return files
+}
+
+// logRepo returns the repository of a log file, without the
+// fireflyframework- prefix.
+func logRepo(path string) string {
+	return shortName(strings.TrimSuffix(filepath.Base(path), ".log"))
+}
+
+func printLogLine(repo string, width int, line string) {
+	fmt.Printf("%s %s\n", ui.StylePrimary.Render(fmt.Sprintf("%-*s │", width, repo)), line)
+}
+
+func statusMark(status string) string {
+	switch status {
+	case runlog.StatusSuccess:
+		return ui.StyleSuccess.Render("✓")
+	case runlog.StatusFailed:
+		return ui.StyleError.Render("✗")
+	default:
+		return ui.StyleWarning.Render("■")
+	}
+}
+
+// lastLines returns the last n lines of a file and its size, reading it
+// backwards so that large logs are not loaded whole.
+func lastLines(path string, n int) ([]string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	size := info.Size()
+	if n <= 0 {
+		return nil, size, nil
+	}
+
+	const chunk = 64 << 10
+	var data []byte
+	for pos := size; pos > 0 && bytes.Count(data, []byte("\n")) <= n; {
+		step := min(int64(chunk), pos)
+		pos -= step
+		buf := make([]byte, step)
+		if _, err := f.ReadAt(buf, pos); err != nil && err != io.EOF {
+			return nil, 0, err
+		}
+		data = append(buf, data...)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, size, nil
+}
+
+// readFrom returns the content of a file after offset and the offset of its
+// end. A file smaller than offset was rotated or rewritten and is read from
+// its start.
+func readFrom(path string, offset int64) ([]byte, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	data, err := io.ReadAll(f)
+	return data, offset + int64(len(data)), err
+}
+
+// grepFile prints the lines of a file matching re and returns their count.
+func grepFile(path string, re *regexp.Regexp, prefix string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	matches := 0
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 4<<20)
+	for n := 1; sc.Scan(); n++ {
+		if line := sc.Text(); re.MatchString(line) {
+			matches++
+			fmt.Printf("%s:%s %s\n", ui.StylePrimary.Render(prefix), ui.StyleMuted.Render(fmt.Sprintf("%d:", n)), line)
+		}
+	}
+	return matches, sc.Err()
+}
@@ -15,6 +15,8 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,12 +24,14 @@ import (
 	"time"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/build"
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 	"github.com/fireflyframework/fireflyframework-cli/internal/java"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
 	"github.com/fireflyframework/fireflyframework-cli/internal/publish"
+	"github.com/fireflyframework/fireflyframework-cli/internal/report"
 	"github.com/fireflyframework/fireflyframework-cli/internal/setup"
 	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
 	"github.com/spf13/cobra"
@@ -39,6 +43,16 @@ var (
 	publishDryRun    bool
 	publishSkipTests bool
 	publishJDKPath   string
+	publishJobs      int
+	publishMemPerJob int
+	publishMavenArgs []string
+
+	publishReport     string
+	publishReportFile string
+
+	publishFailFast      bool
+	publishKeepGoing     bool
+	publishForceContinue bool
 )
 
 var publishCmd = &cobra.Command{
@@ -66,14 +80,21 @@ The publish process runs through the following phases:
 
   Phase 3 — Maven Deploy
     Runs 'mvn deploy' on each affected repository in dependency order with
-    progress bars and per-repo spinners.
+    progress bars and per-repo spinners. Dependents of a failed deploy are
+    reported as blocked (--keep-going, the default); use --fail-fast or
+    --force-continue to change this.
 
   Phase 4 — Python Publish (conditional)
-    If fireflyframework-genai is in scope, publishes the Python package as
-    GitHub Release assets.
+    Repositories the catalog publishes as GitHub Release assets (such as the
+    fireflyframework-genai Python package) are built and uploaded when in
+    scope: with --all, or when named with --repo.
 
   Phase 5 — Summary
-    Reports published/skipped/failed counts and total time.
+    Reports published/skipped/failed/blocked counts and total time.
+
+With --report json|junit|html, a report of every repo's status, SHA, layer,
+duration and log path is written to --report-file (default:
+flywork-publish-report.<ext>), as with 'flywork build'.
 
 Use --all to publish everything regardless of change detection. Use --repo to
 publish a specific repository only. Use --dry-run to preview without publishing.
@@ -84,7 +105,9 @@ Examples:
   flywork publish --repo <name>       Publish a specific repo
   flywork publish --dry-run           Preview what would be published
   flywork publish --skip-tests=false  Run tests during deploy
-  flywork publish --jdk /path/to/jdk  Use a specific JAVA_HOME`,
+  flywork publish --jdk /path/to/jdk  Use a specific JAVA_HOME
+  flywork publish --all --jobs 4      Deploy up to 4 repos in parallel
+  flywork publish --report json       Write flywork-publish-report.json`,
 	RunE: runPublish,
 }
 
@@ -94,6 +117,10 @@ func init() {
 	publishCmd.Flags().BoolVar(&publishDryRun, "dry-run", false, "Show what would be published without publishing")
 	publishCmd.Flags().BoolVar(&publishSkipTests, "skip-tests", true, "Skip tests during deploy (default: true)")
 	publishCmd.Flags().StringVar(&publishJDKPath, "jdk", "", "Explicit JAVA_HOME path")
+	addParallelFlags(publishCmd, &publishJobs, &publishMemPerJob)
+	addMavenArgFlag(publishCmd, &publishMavenArgs)
+	addFailurePolicyFlags(publishCmd, &publishFailFast, &publishKeepGoing, &publishForceContinue)
+	addReportFlags(publishCmd, &publishReport, &publishReportFile)
 	rootCmd.AddCommand(publishCmd)
 }
 
@@ -101,6 +128,12 @@ func runPublish(cmd *cobra.Command, args []string) error {
 	p := ui.NewPrinter()
 	overallStart := time.Now()
 
+	var err error
+	publishReport, publishReportFile, err = report.ResolveTarget("publish", publishReport, publishReportFile)
+	if err != nil {
+		return err
+	}
+
 	p.Header("Publish to GitHub Packages")
 
 	// ═════════════════════════════════════════════════════════════════════════
@@ -172,7 +205,14 @@ func runPublish(cmd *cobra.Command, args []string) error {
 	// ═════════════════════════════════════════════════════════════════════════
 	p.StageHeader(2, "Publish Plan")
 
-	g := dag.FrameworkGraph()
+	g, err := dag.Load(cfg.ReposPath)
+	if err != nil {
+		return fmt.Errorf("dependency graph error: %w", err)
+	}
+	cat, err := catalog.Load(cfg.ReposPath)
+	if err != nil {
+		return fmt.Errorf("repository catalog error: %w", err)
+	}
 
 	manifest, err := build.LoadManifest(build.DefaultManifestPath())
 	if err != nil {
@@ -193,16 +233,34 @@ func runPublish(cmd *cobra.Command, args []string) error {
 			affected[n] = true
 		}
 	} else if publishRepo != "" {
-		if !g.HasNode(publishRepo) {
+		if !g.HasNode(publishRepo) && !cat.Has(publishRepo) {
 			return fmt.Errorf("unknown repository: %s", publishRepo)
 		}
 		p.Info(fmt.Sprintf("Mode: publishing %s", publishRepo))
-		affected = map[string]bool{publishRepo: true}
+		affected = make(map[string]bool)
+		if g.HasNode(publishRepo) {
+			affected[publishRepo] = true
+		}
 	} else {
 		p.Info(fmt.Sprintf("%d repos changed, %d total to publish", len(changed), len(affected)))
 	}
 
-	if len(affected) == 0 {
+	// Non-Maven repos released as GitHub Release assets (e.g. Python
+	// packages) are only published with --all or --repo.
+	var releases []catalog.Repo
+	for _, entry := range cat.Repos {
+		if entry.Publish != catalog.PublishGitHubRelease {
+			continue
+		}
+		if !publishAll && publishRepo != entry.Name {
+			continue
+		}
+		if _, serr := os.Stat(filepath.Join(cfg.ReposPath, entry.Name)); serr == nil {
+			releases = append(releases, entry)
+		}
+	}
+
+	if len(affected) == 0 && len(releases) == 0 {
 		p.Newline()
 		p.Success("Everything is up to date — nothing to publish")
 		return nil
@@ -224,8 +282,17 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		totalToPublish += len(layer)
 	}
 
+	if len(releases) > 0 {
+		p.Newline()
+		p.Info("GitHub Release assets:")
+		for _, entry := range releases {
+			fmt.Printf("    %s %s %s\n", ui.StyleMuted.Render("•"), strings.TrimPrefix(entry.Name, "fireflyframework-"),
+				ui.StyleMuted.Render("("+string(entry.Kind)+")"))
+		}
+	}
+
 	p.Newline()
-	p.Info(fmt.Sprintf("Plan: %d repos to publish across %d layers", totalToPublish, len(layers)))
+	p.Info(fmt.Sprintf("Plan: %d repos to publish across %d layers", totalToPublish+len(releases), len(layers)))
 
 	if publishDryRun {
 		p.Newline()
@@ -240,91 +307,138 @@ func runPublish(cmd *cobra.Command, args []string) error {
 	// ═════════════════════════════════════════════════════════════════════════
 	// Phase 3 — Maven Deploy
 	// ═════════════════════════════════════════════════════════════════════════
-	p.StageHeader(3, "Publishing Maven Artifacts")
-	p.Newline()
+	published, pubSkipped, pubFailed, pubBlocked := 0, 0, 0, 0
+	var results []publish.PublishResult
+	rec := report.NewRecorder("publish", cfg.ReposPath, g, publishJobs)
+	defer writeReport(p, rec, publishReport, publishReportFile)
+	logs := startRunLog(p, "publish", cfg)
+	defer logs.Finish()
+
+	if len(affected) > 0 {
+		p.StageHeader(3, "Publishing Maven Artifacts")
+		p.Newline()
 
-	javaHome := publishJDKPath
-	if javaHome == "" {
-		selectedHome, jdkErr := setup.SelectJDK(cfg.JavaVersion)
-		if jdkErr != nil {
-			p.Warning(jdkErr.Error() + " — using system default")
-		} else {
-			javaHome = selectedHome
+		javaHome := publishJDKPath
+		if javaHome == "" {
+			selectedHome, jdkErr := setup.SelectJDK(cfg.JavaVersion)
+			if jdkErr != nil {
+				p.Warning(jdkErr.Error() + " — using system default")
+			} else {
+				javaHome = selectedHome
+			}
 		}
-	}
 
-	opts := publish.PublishOptions{
-		ReposDir:  cfg.ReposPath,
-		JavaHome:  javaHome,
-		GithubOrg: cfg.GithubOrg,
-		SkipTests: publishSkipTests,
-		ForceAll:  publishAll,
-		DryRun:    false,
-	}
-	if publishRepo != "" {
-		opts.TargetRepos = []string{publishRepo}
-	}
-
-	bar := ui.NewProgressBar(totalToPublish, "published")
-	var activeSpinner *ui.Spinner
-	published, pubSkipped, pubFailed := 0, 0, 0
-	prevLayer := -1
-
-	results, _, err := publish.PublishAllDAG(
-		opts,
-		func(layer int, repo string, idx, total int) {
-			if verbose && layer != prevLayer {
-				if prevLayer >= 0 {
-					bar.Finish()
+		opts := publish.PublishOptions{
+			ReposDir:  cfg.ReposPath,
+			JavaHome:  javaHome,
+			GithubOrg: cfg.GithubOrg,
+			SkipTests: publishSkipTests,
+			ForceAll:  publishAll,
+			DryRun:    false,
+			Maven:     mavenSettings(cfg, publishMavenArgs),
+		}
+		if publishRepo != "" {
+			opts.TargetRepos = []string{publishRepo}
+		}
+		opts.Jobs, opts.HeapMB = publishJobs, heapPerJob(publishJobs, publishMemPerJob)
+		opts.HeapSet = publishMemPerJob > 0
+		printParallelism(p, opts.Jobs, opts.HeapMB)
+		opts.FailurePolicy = failurePolicy(publishFailFast, publishForceContinue)
+		opts.Logs = logs
+
+		bar := ui.NewProgressBar(totalToPublish, "published")
+		spinner := ui.NewJobSpinner("Publishing")
+		opts.OnOutput = liveTail(spinner)
+		prevLayer := -1
+		running := make(map[string]bool)
+		var stopped []string
+
+		ctx, stop := interruptContext()
+		defer stop()
+		results, _, err = publish.PublishAllDAG(
+			ctx, opts,
+			func(layer int, repo string, idx, total int) {
+				running[repo] = true
+				rec.Start(repo)
+				if verbose && layer > prevLayer {
+					if prevLayer >= 0 {
+						bar.Finish()
+					}
+					p.LayerHeader(layer, len(layers), len(layers[layer]))
+					prevLayer = layer
 				}
-				p.LayerHeader(layer, len(layers), len(layers[layer]))
-				prevLayer = layer
-			}
-			activeSpinner = ui.NewSpinner(fmt.Sprintf("Publishing %s...", strings.TrimPrefix(repo, "fireflyframework-")))
-			activeSpinner.Start()
-		},
-		func(layer int, repo string, idx, total int, r publish.PublishResult) {
-			if activeSpinner != nil {
-				activeSpinner.Stop(r.Error == nil)
-				activeSpinner = nil
-			}
-
-			switch {
-			case r.Skipped:
-				pubSkipped++
-			case r.Error != nil:
-				pubFailed++
-				p.Error(fmt.Sprintf("%-45s %s", repo, r.Error))
-				if r.LogFile != "" {
-					p.Info(fmt.Sprintf("  Log: %s", r.LogFile))
+				spinner.Add(shortName(repo))
+			},
+			func(layer int, repo string, idx, total int, r publish.PublishResult) {
+				rec.Finish(publishReportRepo(layer, r))
+				if r.Interrupted {
+					if running[repo] {
+						stopped = append(stopped, repo)
+					}
+					return
+				}
+				delete(running, repo)
+				spinner.Done(shortName(repo), r.Error == nil)
+
+				switch {
+				case r.Skipped:
+					pubSkipped++
+				case r.Blocked:
+					pubBlocked++
+					printBlocked(repo, r.BlockedBy)
+				case r.Error != nil:
+					pubFailed++
+					p.Error(fmt.Sprintf("%-45s %s", repo, r.Error))
+					if r.LogFile != "" {
+						p.Info(fmt.Sprintf("  Log: %s", r.LogFile))
+					}
+				default:
+					published++
 				}
-			default:
-				published++
-			}
 
-			bar.Increment()
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("publish error: %w", err)
-	}
+				bar.Increment()
+			},
+		)
+		if errors.Is(err, context.Canceled) {
+			spinner.Stop()
+			printInterrupted(p, stopped, "flywork publish")
+			return errInterrupted
+		}
+		if err != nil {
+			return fmt.Errorf("publish error: %w", err)
+		}
 
-	bar.Finish()
+		bar.Finish()
+	}
 
 	// ═════════════════════════════════════════════════════════════════════════
-	// Phase 4 — Python Publish (if genai in scope)
+	// Phase 4 — Python Publish (catalog repos with publish: github-release)
 	// ═════════════════════════════════════════════════════════════════════════
-	genaiDir := filepath.Join(cfg.ReposPath, "fireflyframework-genai")
-	if _, serr := os.Stat(genaiDir); serr == nil {
-		if publishAll || publishRepo == "fireflyframework-genai" {
-			p.StageHeader(4, "Publishing Python Package")
-
-			err := publish.PublishPython(genaiDir, cfg.GithubOrg)
+	if len(releases) > 0 {
+		p.StageHeader(4, "Publishing Python Packages")
+
+		ctx, stop := interruptContext()
+		defer stop()
+		for _, entry := range releases {
+			// Release assets are published after every Maven layer.
+			entryReport := report.Repo{Name: entry.Name, Layer: len(layers), Status: report.StatusPublished}
+			rec.Start(entry.Name)
+			err := publish.PublishPython(ctx, filepath.Join(cfg.ReposPath, entry.Name), cfg.GithubOrg)
+			if ctx.Err() != nil {
+				entryReport.Status = report.StatusInterrupted
+				rec.Finish(entryReport)
+				printInterrupted(p, nil, "flywork publish")
+				return errInterrupted
+			}
 			if err != nil {
-				p.Error(fmt.Sprintf("Python publish failed: %s", err))
+				entryReport.Status, entryReport.Error = report.StatusFailed, err.Error()
+			}
+			rec.Finish(entryReport)
+			if err != nil {
+				p.Error(fmt.Sprintf("%s: Python publish failed: %s", entry.Name, err))
 				pubFailed++
 			} else {
-				p.Success("Python package published as GitHub Release assets")
+				p.Success(fmt.Sprintf("%s published as GitHub Release assets", entry.Name))
 				published++
 			}
 		}
@@ -336,7 +450,7 @@ func runPublish(cmd *cobra.Command, args []string) error {
 	elapsed := time.Since(overallStart).Truncate(time.Second)
 
 	status := "Publish Complete"
-	if pubFailed > 0 {
+	if pubFailed > 0 || pubBlocked > 0 {
 		status = "Publish Incomplete"
 	}
 
@@ -344,8 +458,16 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		fmt.Sprintf("Published     %d", published),
 		fmt.Sprintf("Skipped       %d", pubSkipped),
 		fmt.Sprintf("Failed        %d", pubFailed),
+	}
+	if pubBlocked > 0 {
+		summaryLines = append(summaryLines, fmt.Sprintf("Blocked       %d", pubBlocked))
+	}
+	summaryLines = append(summaryLines,
 		fmt.Sprintf("Layers        %d", len(layers)),
 		fmt.Sprintf("Total time    %s", elapsed),
+	)
+	if len(logs.Counts()) > 0 {
+		summaryLines = append(summaryLines, fmt.Sprintf("Deploy logs   %s", logs.Dir()))
 	}
 	p.SummaryBox(status, summaryLines)
 
@@ -358,6 +480,28 @@ func runPublish(cmd *cobra.Command, args []string) error {
 			}
 		}
 	}
+	printBlockedSummary(p, len(results), func(i int) (string, string) {
+		return results[i].Repo, results[i].BlockedBy
+	})
 
 	return nil
 }
+
+// publishReportRepo converts a publish result into its report entry.
+func publishReportRepo(layer int, r publish.PublishResult) report.Repo {
+	entry := report.Repo{Name: r.Repo, Layer: layer, LogFile: r.LogFile, BlockedBy: r.BlockedBy}
+	switch {
+	case r.Interrupted:
+		entry.Status = report.StatusInterrupted
+	case r.Skipped:
+		entry.Status = report.StatusSkipped
+	case r.Blocked:
+		entry.Status = report.StatusBlocked
+	case r.Error != nil:
+		entry.Status = report.StatusFailed
+		entry.Error = r.Error.Error()
+	default:
+		entry.Status = report.StatusPublished
+	}
+	return entry
+}
@@ -0,0 +1,323 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var reposCmd = &cobra.Command{
+	Use:   "repos",
+	Short: "List the repositories in the repo catalog",
+	Long: `Commands for viewing the repository catalog: the single list of
+repositories that setup clones and that build, publish, fwversion and doctor
+operate on. Each entry records the repository's kind (maven, python, docs),
+git URL, default branch, publish target, version files, owners and tags.
+
+The catalog is embedded in the CLI and can be extended or overridden in
+~/.flywork/repos.yaml (global) and <repos_path>/.flywork/repos.yaml (per
+workspace). Repositories declared in a dag.yaml overlay are added as Maven
+repositories. Dependency edges are not part of the catalog; see 'flywork dag'.
+
+Available Subcommands:
+  list       List catalog repositories, optionally filtered by kind or tag
+  info       Show every catalog field of one repository
+
+Examples:
+  flywork repos list
+  flywork repos list --kind python
+  flywork repos list --tag idp --json
+  flywork repos info genai`,
+}
+
+var (
+	reposListKind string
+	reposListTag  string
+	reposListJSON bool
+)
+
+var reposListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List catalog repositories",
+	Long: `Lists every repository in the catalog with its kind, publish target, DAG
+layer and whether it is cloned into repos_path. Disagreements between the
+catalog and the dependency graph are reported at the end.`,
+	RunE: runReposList,
+}
+
+var reposInfoJSON bool
+
+var reposInfoCmd = &cobra.Command{
+	Use:   "info <repo>",
+	Short: "Show the catalog entry of a repository",
+	Long: `Shows every catalog field of a repository, with the clone URL and branch
+resolved against the configured GitHub organisation and branch, and its
+direct dependencies and dependents in the DAG. The fireflyframework- prefix
+may be omitted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReposInfo,
+}
+
+func init() {
+	reposListCmd.Flags().StringVar(&reposListKind, "kind", "", "Only list repositories of this kind (maven, python, docs)")
+	reposListCmd.Flags().StringVar(&reposListTag, "tag", "", "Only list repositories with this tag")
+	reposListCmd.Flags().BoolVar(&reposListJSON, "json", false, "Output as JSON")
+	reposInfoCmd.Flags().BoolVar(&reposInfoJSON, "json", false, "Output as JSON")
+
+	reposCmd.AddCommand(reposListCmd)
+	reposCmd.AddCommand(reposInfoCmd)
+	rootCmd.AddCommand(reposCmd)
+}
+
+// reposEntry is a catalog entry resolved against the config and the DAG.
+type reposEntry struct {
+	catalog.Repo
+	CloneURL    string `json:"clone_url"`
+	CloneBranch string `json:"clone_branch"`
+	Layer       *int   `json:"layer,omitempty"`
+	Cloned      bool   `json:"cloned"`
+}
+
+func loadCatalog() (*config.Config, *catalog.Catalog, *dag.Graph, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cat, err := catalog.Load(cfg.ReposPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("repository catalog error: %w", err)
+	}
+	g, err := dag.Load(cfg.ReposPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("dependency graph error: %w", err)
+	}
+	return cfg, cat, g, nil
+}
+
+func resolveEntry(cfg *config.Config, r catalog.Repo, layerOf map[string]int) reposEntry {
+	e := reposEntry{
+		Repo:        r,
+		CloneURL:    r.CloneURL(cfg.GithubOrg),
+		CloneBranch: r.CloneBranch(cfg.Branch),
+	}
+	if l, ok := layerOf[r.Name]; ok {
+		e.Layer = &l
+	}
+	if _, err := os.Stat(filepath.Join(cfg.ReposPath, r.Name)); err == nil {
+		e.Cloned = true
+	}
+	return e
+}
+
+func layerMap(g *dag.Graph) map[string]int {
+	out := make(map[string]int)
+	layers, err := g.Layers()
+	if err != nil {
+		return out
+	}
+	for i, layer := range layers {
+		for _, repo := range layer {
+			out[repo] = i
+		}
+	}
+	return out
+}
+
+func runReposList(_ *cobra.Command, _ []string) error {
+	cfg, cat, g, err := loadCatalog()
+	if err != nil {
+		return err
+	}
+	switch catalog.Kind(reposListKind) {
+	case "", catalog.KindMaven, catalog.KindPython, catalog.KindDocs:
+	default:
+		return fmt.Errorf("unknown kind %q (want maven, python or docs)", reposListKind)
+	}
+
+	layerOf := layerMap(g)
+	entries := []reposEntry{}
+	for _, r := range cat.Repos {
+		if reposListKind != "" && string(r.Kind) != reposListKind {
+			continue
+		}
+		if reposListTag != "" && !r.HasTag(reposListTag) {
+			continue
+		}
+		entries = append(entries, resolveEntry(cfg, r, layerOf))
+	}
+	mismatch := cat.Check(g)
+
+	if reposListJSON {
+		out := struct {
+			Repos    []reposEntry     `json:"repos"`
+			Count    int              `json:"count"`
+			Sources  []string         `json:"sources"`
+			Mismatch catalog.Mismatch `json:"mismatch"`
+		}{
+			Repos:    entries,
+			Count:    len(entries),
+			Sources:  cat.Sources,
+			Mismatch: mismatch,
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	p := ui.NewPrinter()
+	p.Header("Repository Catalog")
+	p.Newline()
+
+	cloned := 0
+	for _, e := range entries {
+		layer := "-"
+		if e.Layer != nil {
+			layer = fmt.Sprintf("L%d", *e.Layer)
+		}
+		mark := ui.StyleMuted.Render("○")
+		if e.Cloned {
+			mark = ui.StylePrimary.Render("●")
+			cloned++
+		}
+		fmt.Printf("  %s %-40s %-7s %-16s %-4s %s\n", mark, shortName(e.Name), e.Kind, e.Publish, layer,
+			ui.StyleMuted.Render(strings.Join(e.Tags, ", ")))
+	}
+
+	p.Newline()
+	p.Info(fmt.Sprintf("%d repositories, %d cloned (sources: %s)", len(entries), cloned, strings.Join(cat.Sources, ", ")))
+
+	if !mismatch.Empty() {
+		p.Newline()
+		printMismatch(p, mismatch)
+	}
+	return nil
+}
+
+// printMismatch warns about every disagreement between the catalog and the
+// DAG.
+func printMismatch(p *ui.Printer, m catalog.Mismatch) {
+	for _, repo := range m.NotInGraph {
+		p.Warning(fmt.Sprintf("%s is a Maven repo in the catalog but not in the DAG", repo))
+	}
+	for _, repo := range m.NotInCatalog {
+		p.Warning(fmt.Sprintf("%s is in the DAG but not in the catalog", repo))
+	}
+	for _, repo := range m.NotMaven {
+		p.Warning(fmt.Sprintf("%s is in the DAG but not a Maven repo in the catalog", repo))
+	}
+}
+
+func runReposInfo(_ *cobra.Command, args []string) error {
+	cfg, cat, g, err := loadCatalog()
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	if !cat.Has(name) {
+		name = "fireflyframework-" + name
+	}
+	r, ok := cat.Get(name)
+	if !ok {
+		return fmt.Errorf("unknown repository: %s", args[0])
+	}
+	e := resolveEntry(cfg, r, layerMap(g))
+
+	var deps, dependents []string
+	if g.HasNode(name) {
+		deps = g.DependenciesOf(name)
+		dependents = g.DependentsOf(name)
+		sort.Strings(deps)
+		sort.Strings(dependents)
+	}
+
+	if reposInfoJSON {
+		out := struct {
+			reposEntry
+			DependsOn  []string `json:"depends_on"`
+			Dependents []string `json:"dependents"`
+		}{
+			reposEntry: e,
+			DependsOn:  deps,
+			Dependents: dependents,
+		}
+		if out.DependsOn == nil {
+			out.DependsOn = []string{}
+		}
+		if out.Dependents == nil {
+			out.Dependents = []string{}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	orNone := func(values []string) string {
+		if len(values) == 0 {
+			return ui.StyleMuted.Render("none")
+		}
+		return strings.Join(values, ", ")
+	}
+	short := func(values []string) []string {
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = shortName(v)
+		}
+		return out
+	}
+
+	p := ui.NewPrinter()
+	p.Header(e.Name)
+	p.KeyValue("Kind", string(e.Kind))
+	p.KeyValue("URL", e.CloneURL)
+	p.KeyValue("Branch", e.CloneBranch)
+	p.KeyValue("Publish", string(e.Publish))
+	p.KeyValue("Version files", orNone(e.VersionFiles))
+	p.KeyValue("Owners", orNone(e.Owners))
+	p.KeyValue("Tags", orNone(e.Tags))
+	if e.Description != "" {
+		p.KeyValue("Description", e.Description)
+	}
+	if e.Layer != nil {
+		p.KeyValue("DAG layer", fmt.Sprintf("%d", *e.Layer))
+		p.KeyValue("Depends on", orNone(short(deps)))
+		p.KeyValue("Dependents", orNone(short(dependents)))
+	} else {
+		p.KeyValue("DAG layer", ui.StyleMuted.Render("not in DAG"))
+	}
+	cloned := "no"
+	if e.Cloned {
+		cloned = filepath.Join(cfg.ReposPath, e.Name)
+	}
+	p.KeyValue("Cloned", cloned)
+	return nil
+}
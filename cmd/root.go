@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -57,6 +58,11 @@ var skipBanner = map[string]bool{
 	"config reset": true,
 	"help": true,
 	"completion": true,
+	"dag export": true,
+	"dag shard":  true,
+	"logs show":  true,
+	"logs tail":  true,
+	"logs grep":  true,
 }
 
 func shouldSkipBanner(cmd *cobra.Command) bool {
@@ -64,9 +70,11 @@ func shouldSkipBanner(cmd *cobra.Command) bool {
 	if cmd.Flags().Changed("help") {
 		return true
 	}
-	// Skip if --json flag was set (prevents banner from corrupting JSON output)
-	if f := cmd.Flags().Lookup("json"); f != nil && f.Changed {
-		return true
+	// Skip if --json or --matrix was set (prevents banner from corrupting JSON output)
+	for _, name := range []string{"json", "matrix"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return true
+		}
 	}
 	// Build command path like "config get" (stop at root)
 	parts := []string{}
@@ -122,6 +130,9 @@ func Execute() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	if err := rootCmd.Execute(); err != nil {
+		if errors.Is(err, errInterrupted) {
+			os.Exit(130)
+		}
 		fmt.Fprintln(os.Stderr, lipgloss.NewStyle().Foreground(lipgloss.Color("#DC3545")).Render("Error: "+err.Error()))
 		os.Exit(1)
 	}
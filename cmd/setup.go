@@ -15,6 +15,8 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -24,17 +26,24 @@ import (
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 	"github.com/fireflyframework/fireflyframework-cli/internal/java"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/report"
 	"github.com/fireflyframework/fireflyframework-cli/internal/setup"
 	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	skipTests    bool
-	setupRetry   bool
-	setupFresh   bool
-	setupFetch   bool
-	setupJDKPath string
+	skipTests      bool
+	setupRetry     bool
+	setupFresh     bool
+	setupFetch     bool
+	setupJDKPath   string
+	setupJobs      int
+	setupMemPerJob int
+	setupMavenArgs []string
+
+	setupReport     string
+	setupReportFile string
 )
 
 var setupCmd = &cobra.Command{
@@ -59,7 +68,8 @@ The setup process runs through the following phases:
   Phase 3 — Installing Artifacts
     Runs 'mvn clean install' on each repository in dependency order. Per-repo
     spinners show elapsed time. When --skip-tests is not provided, the CLI
-    interactively asks whether to run tests (default: yes).
+    interactively asks whether to run tests (default: yes). With --jobs N, up
+    to N repos whose dependencies are installed run at once.
 
   Post-Install — Retry Loop
     If any repositories fail to install, the CLI offers to retry them
@@ -71,6 +81,10 @@ Resume and retry behavior:
   or start fresh. Use --retry to skip the prompt and directly retry failures.
   Use --fresh to ignore any previous manifest and start over.
 
+With --report json|junit|html, a report of every repo's status, SHA, layer,
+install duration and log path is written to --report-file (default:
+flywork-setup-report.<ext>). Repos that failed to clone are reported as failed.
+
 Examples:
   flywork setup                    Full interactive setup
   flywork setup --skip-tests       Skip tests during Maven install
@@ -78,6 +92,8 @@ Examples:
   flywork setup --fresh            Ignore previous manifest, start from scratch
   flywork setup --fetch-updates    Also fetch updates for already-cloned repos
   flywork setup --jdk /path/to/jdk Use a specific JDK instead of auto-detection
+  flywork setup --jobs 4           Install up to 4 repos in parallel
+  flywork setup --report html      Write an HTML timeline of the install
   flywork setup -v                 Verbose output with DAG layer details`,
 	RunE: runSetup,
 }
@@ -88,6 +104,9 @@ func init() {
 	setupCmd.Flags().BoolVar(&setupFresh, "fresh", false, "Force a fresh setup, ignoring any previous manifest")
 	setupCmd.Flags().BoolVar(&setupFetch, "fetch-updates", false, "Fetch latest changes for already-cloned repos")
 	setupCmd.Flags().StringVar(&setupJDKPath, "jdk", "", "Explicit JAVA_HOME path (skip JDK picker)")
+	addParallelFlags(setupCmd, &setupJobs, &setupMemPerJob)
+	addMavenArgFlag(setupCmd, &setupMavenArgs)
+	addReportFlags(setupCmd, &setupReport, &setupReportFile)
 	rootCmd.AddCommand(setupCmd)
 }
 
@@ -95,6 +114,12 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	p := ui.NewPrinter()
 	overallStart := time.Now()
 
+	var err error
+	setupReport, setupReportFile, err = report.ResolveTarget("setup", setupReport, setupReportFile)
+	if err != nil {
+		return err
+	}
+
 	p.Header("Firefly Framework Setup")
 
 	// ═════════════════════════════════════════════════════════════════════════
@@ -143,14 +168,16 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create repos directory: %w", err)
 	}
 
-	g := dag.FrameworkGraph()
-	totalRepos := g.NodeCount()
-	dagLayers, dagErr := g.Layers()
+	// Clone layers: the DAG layers plus the catalog repos outside the DAG
+	dagLayers, dagErr := setup.CloneLayers(cfg.ReposPath)
 	if dagErr != nil {
 		return fmt.Errorf("dependency graph error: %w", dagErr)
 	}
-
-	order, _ := g.FlatOrder()
+	var order []string
+	for _, layer := range dagLayers {
+		order = append(order, layer...)
+	}
+	totalRepos := len(order)
 
 	p.Info(fmt.Sprintf("Resolved dependency graph: %d repositories, %d layers", totalRepos, len(dagLayers)))
 	p.Info(fmt.Sprintf("Target: %s", cfg.ReposPath))
@@ -268,10 +295,24 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	cloneBar := ui.NewProgressBar(totalRepos, "cloned")
 	cloned, skipped, cloneFailed := 0, 0, 0
 	prevCloneLayer := -1
+	var stopped []string
 
+	// The report covers the install; clones only appear when they fail.
+	rec := report.NewRecorder("setup", cfg.ReposPath, nil, setupJobs)
+	defer writeReport(p, rec, setupReport, setupReportFile)
+
+	ctx, stop := interruptContext()
 	_, _, dagErr = setup.CloneAllDAG(
-		cfg.GithubOrg, cfg.ReposPath, cfg.Branch, manifest,
+		ctx, cfg.GithubOrg, cfg.ReposPath, cfg.Branch, manifest,
 		func(layer int, repo string, idx, total int, r setup.CloneResult) {
+			if r.Interrupted {
+				rec.Finish(report.Repo{Name: repo, Layer: layer, Status: report.StatusInterrupted})
+				stopped = append(stopped, repo)
+				return
+			}
+			if r.Error != nil {
+				rec.Finish(report.Repo{Name: repo, Layer: layer, Status: report.StatusFailed, Error: "git clone: " + r.Error.Error()})
+			}
 			if verbose && layer != prevCloneLayer {
 				if prevCloneLayer >= 0 {
 					cloneBar.Finish()
@@ -299,6 +340,11 @@ func runSetup(cmd *cobra.Command, args []string) error {
 			cloneBar.Increment()
 		},
 	)
+	stop()
+	if errors.Is(dagErr, context.Canceled) {
+		printInterrupted(p, stopped, "flywork setup")
+		return errInterrupted
+	}
 	if dagErr != nil {
 		return fmt.Errorf("dependency graph error: %w", dagErr)
 	}
@@ -321,7 +367,8 @@ func runSetup(cmd *cobra.Command, args []string) error {
 			fetchBar := ui.NewProgressBar(len(clonedRepos), "fetched")
 			fetchFailed := 0
 
-			setup.FetchUpdates(cfg.ReposPath, clonedRepos,
+			ctx, stop := interruptContext()
+			setup.FetchUpdates(ctx, cfg.ReposPath, clonedRepos,
 				func(repo string, idx, total int, r setup.FetchResult) {
 					if r.Error != nil {
 						fetchFailed++
@@ -333,6 +380,11 @@ func runSetup(cmd *cobra.Command, args []string) error {
 				},
 			)
 
+			stop()
+			if ctx.Err() != nil {
+				printInterrupted(p, nil, "flywork setup")
+				return errInterrupted
+			}
 			fetchBar.Finish()
 			p.Newline()
 			if fetchFailed > 0 {
@@ -353,6 +405,21 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	p.StageHeader(3, "Installing Artifacts")
 	p.Newline()
 
+	// Now that the repos are on disk, resolve the graph from their pom.xml
+	// files instead of the embedded bootstrap graph used for cloning.
+	installGraph, dagErr := dag.Load(cfg.ReposPath)
+	if dagErr != nil {
+		return fmt.Errorf("dependency graph error: %w", dagErr)
+	}
+	installLayers, dagErr := installGraph.Layers()
+	if dagErr != nil {
+		return fmt.Errorf("dependency graph error: %w", dagErr)
+	}
+	if verbose {
+		p.Info(fmt.Sprintf("Workspace dependency graph: %d repositories, %d layers", installGraph.NodeCount(), len(installLayers)))
+	}
+	rec.SetGraph(installGraph)
+
 	var reposFilter map[string]bool
 	if retryMode {
 		pending := manifest.PendingInstalls()
@@ -367,29 +434,44 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	installBar := ui.NewProgressBar(totalRepos, "installed")
-	var activeSpinner *ui.Spinner
+	heapMB := heapPerJob(setupJobs, setupMemPerJob)
+	printParallelism(p, setupJobs, heapMB)
+
+	logs := startRunLog(p, "setup", cfg)
+	defer logs.Finish()
+
+	installBar := ui.NewProgressBar(installGraph.NodeCount(), "installed")
+	spinner := ui.NewJobSpinner("Building")
 	installed, installSkipped, installFailed := 0, 0, 0
 	prevInstallLayer := -1
+	running := make(map[string]bool)
+	stopped = nil
 
+	ctx, stop = interruptContext()
 	_, _, dagErr = setup.InstallAllDAG(
-		cfg.ReposPath, javaHome, skipTests, manifest, reposFilter,
+		ctx, cfg.ReposPath, javaHome, skipTests, setupJobs, heapMB, setupMemPerJob > 0, mavenSettings(cfg, setupMavenArgs), manifest, logs, reposFilter,
 		func(layer int, repo string, idx, total int) {
-			if verbose && layer != prevInstallLayer {
+			running[repo] = true
+			rec.Start(repo)
+			if verbose && layer > prevInstallLayer {
 				if prevInstallLayer >= 0 {
 					installBar.Finish()
 				}
-				p.LayerHeader(layer, len(dagLayers), len(dagLayers[layer]))
+				p.LayerHeader(layer, len(installLayers), len(installLayers[layer]))
 				prevInstallLayer = layer
 			}
-			activeSpinner = ui.NewSpinner(fmt.Sprintf("Building %s...", repo))
-			activeSpinner.Start()
+			spinner.Add(repo)
 		},
 		func(layer int, repo string, idx, total int, r setup.InstallResult) {
-			if activeSpinner != nil {
-				activeSpinner.Stop(r.Error == nil)
-				activeSpinner = nil
+			rec.Finish(installReportRepo(layer, r))
+			if r.Interrupted {
+				if running[repo] {
+					stopped = append(stopped, repo)
+				}
+				return
 			}
+			delete(running, repo)
+			spinner.Done(repo, r.Error == nil)
 
 			switch {
 			case r.Skipped:
@@ -403,7 +485,14 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 			installBar.Increment()
 		},
+		liveTail(spinner),
 	)
+	stop()
+	if errors.Is(dagErr, context.Canceled) {
+		spinner.Stop()
+		printInterrupted(p, stopped, "flywork setup")
+		return errInterrupted
+	}
 	if dagErr != nil {
 		return fmt.Errorf("dependency graph error: %w", dagErr)
 	}
@@ -426,7 +515,7 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		}
 
 		p.Newline()
-		p.Info(fmt.Sprintf("Build logs: %s", setup.LogsDir()))
+		p.Info(fmt.Sprintf("Build logs: %s", logs.Dir()))
 		p.Newline()
 		if !ui.Confirm("Retry failed repositories now?", true) {
 			break
@@ -440,20 +529,30 @@ func runSetup(cmd *cobra.Command, args []string) error {
 			retryFilter[r] = true
 		}
 
-		retryBar := ui.NewProgressBar(totalRepos, "installed")
+		retryBar := ui.NewProgressBar(installGraph.NodeCount(), "installed")
+		retrySpinner := ui.NewJobSpinner("Retrying")
 		installed, installSkipped, installFailed = 0, 0, 0
+		running = make(map[string]bool)
+		stopped = nil
 
+		ctx, stop = interruptContext()
 		_, _, dagErr = setup.InstallAllDAG(
-			cfg.ReposPath, javaHome, skipTests, manifest, retryFilter,
+			ctx, cfg.ReposPath, javaHome, skipTests, setupJobs, heapMB, setupMemPerJob > 0, mavenSettings(cfg, setupMavenArgs), manifest, logs, retryFilter,
 			func(layer int, repo string, idx, total int) {
-				activeSpinner = ui.NewSpinner(fmt.Sprintf("Retrying %s...", repo))
-				activeSpinner.Start()
+				running[repo] = true
+				rec.Start(repo)
+				retrySpinner.Add(repo)
 			},
 			func(layer int, repo string, idx, total int, r setup.InstallResult) {
-				if activeSpinner != nil {
-					activeSpinner.Stop(r.Error == nil)
-					activeSpinner = nil
+				rec.Finish(installReportRepo(layer, r))
+				if r.Interrupted {
+					if running[repo] {
+						stopped = append(stopped, repo)
+					}
+					return
 				}
+				delete(running, repo)
+				retrySpinner.Done(repo, r.Error == nil)
 
 				switch {
 				case r.Skipped:
@@ -467,7 +566,14 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 				retryBar.Increment()
 			},
+			liveTail(retrySpinner),
 		)
+		stop()
+		if errors.Is(dagErr, context.Canceled) {
+			retrySpinner.Stop()
+			printInterrupted(p, stopped, "flywork setup")
+			return errInterrupted
+		}
 		if dagErr != nil {
 			return fmt.Errorf("dependency graph error: %w", dagErr)
 		}
@@ -501,12 +607,12 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		fmt.Sprintf("Repositories  %d", s.Total),
 		fmt.Sprintf("Cloned        %d  (skipped %d, failed %d)", s.ClonesOK, skipped, s.ClonesFailed),
 		fmt.Sprintf("Installed     %d  (skipped %d, failed %d)", s.InstallsOK, installSkipped, s.InstallsFailed),
-		fmt.Sprintf("DAG layers    %d", len(dagLayers)),
+		fmt.Sprintf("DAG layers    %d", len(installLayers)),
 		fmt.Sprintf("Total time    %s", elapsed),
 		fmt.Sprintf("Manifest      %s", manifestPath),
 	}
-	if s.InstallsFailed > 0 {
-		summaryLines = append(summaryLines, fmt.Sprintf("Build logs    %s", setup.LogsDir()))
+	if len(logs.Counts()) > 0 {
+		summaryLines = append(summaryLines, fmt.Sprintf("Build logs    %s", logs.Dir()))
 	}
 	p.SummaryBox(status, summaryLines)
 
@@ -514,9 +620,26 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		p.Newline()
 		p.Info("Run 'flywork setup --retry' to retry failed repositories")
 		if s.InstallsFailed > 0 {
-			p.Info(fmt.Sprintf("Check build logs for details: %s", setup.LogsDir()))
+			p.Info(fmt.Sprintf("Check build logs for details: %s", logs.Dir()))
 		}
 	}
 
 	return nil
 }
+
+// installReportRepo converts an install result into its report entry.
+func installReportRepo(layer int, r setup.InstallResult) report.Repo {
+	entry := report.Repo{Name: r.Repo, Layer: layer, LogFile: r.LogFile}
+	switch {
+	case r.Interrupted:
+		entry.Status = report.StatusInterrupted
+	case r.Skipped:
+		entry.Status = report.StatusSkipped
+	case r.Error != nil:
+		entry.Status = report.StatusFailed
+		entry.Error = r.Error.Error()
+	default:
+		entry.Status = report.StatusBuilt
+	}
+	return entry
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
@@ -25,6 +26,7 @@ import (
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 	"github.com/fireflyframework/fireflyframework-cli/internal/java"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/report"
 	"github.com/fireflyframework/fireflyframework-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +35,11 @@ var (
 	updatePullOnly    bool
 	updateRepo        string
 	updateSkipTests   bool
+	updateJobs        int
+	updateMemPerJob   int
+	updateMavenArgs   []string
+	updateReport      string
+	updateReportFile  string
 )
 
 var updateCmd = &cobra.Command{
@@ -52,15 +59,20 @@ The update process has two phases:
     Runs 'mvn clean install' on each repository in dependency order.
     Per-repo spinners show elapsed time. When --skip-tests is not provided,
     the CLI interactively asks whether to run tests (default: yes).
+    With --jobs N, up to N repos whose dependencies are installed run at once.
 
 Use --repo to update a single repository by name (e.g. fireflyframework-utils).
 Use --pull-only to only fetch the latest code without running Maven install.
+Use --report json|junit|html to write a report of every repo's status, SHA,
+layer and duration to --report-file (default: flywork-update-report.<ext>).
 
 Examples:
   flywork update                                  Pull + install all repos
   flywork update --skip-tests                     Skip tests during install
   flywork update --pull-only                      Only git pull, skip Maven
   flywork update --repo fireflyframework-utils    Update a single repository
+  flywork update --jobs 4                         Install up to 4 repos in parallel
+  flywork update --report junit                   Write flywork-update-report.xml
   flywork update -v                               Verbose with layer details`,
 	RunE: runUpdate,
 }
@@ -69,6 +81,9 @@ func init() {
 	updateCmd.Flags().BoolVar(&updatePullOnly, "pull-only", false, "Only git pull, skip maven install")
 	updateCmd.Flags().StringVar(&updateRepo, "repo", "", "Update a single repository by name")
 	updateCmd.Flags().BoolVar(&updateSkipTests, "skip-tests", false, "Skip running tests during Maven install")
+	addParallelFlags(updateCmd, &updateJobs, &updateMemPerJob)
+	addMavenArgFlag(updateCmd, &updateMavenArgs)
+	addReportFlags(updateCmd, &updateReport, &updateReportFile)
 	rootCmd.AddCommand(updateCmd)
 }
 
@@ -76,6 +91,12 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	p := ui.NewPrinter()
 	overallStart := time.Now()
 
+	var err error
+	updateReport, updateReportFile, err = report.ResolveTarget("update", updateReport, updateReportFile)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -98,7 +119,14 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Resolve DAG order
-	g := dag.FrameworkGraph()
+	g, dagErr := dag.Load(cfg.ReposPath)
+	if dagErr != nil {
+		return fmt.Errorf("dependency graph error: %w", dagErr)
+	}
+	overlay, dagErr := dag.LoadOverlay(cfg.ReposPath)
+	if dagErr != nil {
+		return fmt.Errorf("dependency graph error: %w", dagErr)
+	}
 	order, dagErr := g.FlatOrder()
 	if dagErr != nil {
 		return fmt.Errorf("dependency graph error: %w", dagErr)
@@ -142,10 +170,28 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	pullBar := ui.NewProgressBar(len(repos), "pulled")
 	pulled, pullSkipped, pullFailed := 0, 0, 0
 
+	layerOf := make(map[string]int, len(order))
+	for i, layer := range layers {
+		for _, repo := range layer {
+			layerOf[repo] = i
+		}
+	}
+	rec := report.NewRecorder("update", cfg.ReposPath, g, updateJobs)
+	defer writeReport(p, rec, updateReport, updateReportFile)
+	// A failed pull stays the repo's reported status even if its install
+	// succeeds afterwards, since the installed code is stale.
+	pullErrors := make(map[string]error)
+
+	ctx, stop := interruptContext()
+	defer stop()
 	for _, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
 		repoDir := filepath.Join(cfg.ReposPath, repo)
 		if _, serr := os.Stat(repoDir); os.IsNotExist(serr) {
 			pullSkipped++
+			rec.Finish(report.Repo{Name: repo, Layer: layerOf[repo], Status: report.StatusSkipped})
 			if verbose {
 				p.Warning(fmt.Sprintf("%-45s not cloned (run 'flywork setup')", repo))
 			}
@@ -153,11 +199,20 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		if pullErr := git.Pull(repoDir); pullErr != nil {
+		rec.Start(repo)
+		pullErr := git.Pull(ctx, repoDir)
+		if ctx.Err() != nil {
+			rec.Finish(report.Repo{Name: repo, Layer: layerOf[repo], Status: report.StatusInterrupted})
+			break
+		}
+		if pullErr != nil {
 			pullFailed++
+			pullErrors[repo] = pullErr
+			rec.Finish(report.Repo{Name: repo, Layer: layerOf[repo], Status: report.StatusFailed, Error: "git pull: " + pullErr.Error()})
 			p.Error(fmt.Sprintf("%-45s %s", repo, pullErr))
 		} else {
 			pulled++
+			rec.Finish(report.Repo{Name: repo, Layer: layerOf[repo], Status: report.StatusPulled})
 			if verbose {
 				p.Success(fmt.Sprintf("%-45s pulled", repo))
 			}
@@ -165,6 +220,10 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		pullBar.Increment()
 	}
 
+	if ctx.Err() != nil {
+		printInterrupted(p, nil, "flywork update")
+		return errInterrupted
+	}
 	pullBar.Finish()
 	p.Newline()
 	p.Info(fmt.Sprintf("Pull: %d pulled, %d skipped, %d failed", pulled, pullSkipped, pullFailed))
@@ -177,39 +236,87 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		// ── Phase 2: Maven install ─────────────────────────────────────────────
 		p.StageHeader(2, "Installing Artifacts")
 
+		installGraph := g
+		if updateRepo != "" {
+			installGraph = g.Subgraph(map[string]bool{updateRepo: true})
+		}
+		runOpts := maven.RunOptions{JavaHome: javaHome, HeapMB: heapPerJob(updateJobs, updateMemPerJob), HeapSet: updateMemPerJob > 0}
+		mavenGlobal := mavenSettings(cfg, updateMavenArgs)
+		printParallelism(p, updateJobs, runOpts.HeapMB)
+
+		logs := startRunLog(p, "update", cfg)
+		defer logs.Finish()
+
 		installBar := ui.NewProgressBar(len(repos), "installed")
-		var activeSpinner *ui.Spinner
+		spinner := ui.NewJobSpinner("Building")
 		installed, installFailed := 0, 0
+		var mu sync.Mutex
 
-		for i, repo := range repos {
+		schedErr := dag.Schedule(installGraph, updateJobs, dag.ForceContinue, func(_ int, repo string, _ int) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			entry := report.Repo{Name: repo, Layer: layerOf[repo], Status: report.StatusBuilt}
 			repoDir := filepath.Join(cfg.ReposPath, repo)
-			if _, serr := os.Stat(repoDir); os.IsNotExist(serr) {
+			settings := overlay.Settings(repo)
+			if _, serr := os.Stat(repoDir); os.IsNotExist(serr) || settings.Skip {
+				entry.Status = report.StatusSkipped
+				rec.Finish(entry)
+				mu.Lock()
 				installBar.Increment()
-				continue
+				mu.Unlock()
+				return true
 			}
 
-			// Start spinner
-			activeSpinner = ui.NewSpinner(fmt.Sprintf("Building %s...", repo))
-			activeSpinner.Start()
-
-		var installErr error
-			if javaHome != "" {
-				installErr = maven.InstallQuietWithJava(repoDir, javaHome, updateSkipTests)
-			} else {
-				installErr = maven.InstallQuiet(repoDir, updateSkipTests)
+			spinner.Add(repo)
+			rec.Start(repo)
+			repoSkipTests := settings.ResolveSkipTests(updateSkipTests)
+			repoOpts := runOpts
+			repoOpts.Maven = mavenGlobal.Merge(settings.Maven())
+			repoOpts.LogFile = logs.Path(repo)
+			if tail := liveTail(spinner); tail != nil {
+				repoOpts.Tail = func(line string) { tail(repo, line) }
 			}
+			_, installErr := maven.InstallWithOptions(ctx, repoDir, repoOpts, repoSkipTests)
+			if ctx.Err() != nil {
+				logs.Record(repo, ctx.Err())
+				entry.Status = report.StatusInterrupted
+				rec.Finish(entry)
+				return false
+			}
+			spinner.Done(repo, installErr == nil)
+			entry.LogFile = logs.Record(repo, installErr)
+
+			switch {
+			case installErr != nil:
+				entry.Status, entry.Error = report.StatusFailed, installErr.Error()
+			case pullErrors[repo] != nil:
+				entry.Status, entry.Error = report.StatusFailed, "git pull: "+pullErrors[repo].Error()
+			}
+			rec.Finish(entry)
 
-			activeSpinner.Stop(installErr == nil)
-
+			mu.Lock()
+			defer mu.Unlock()
 			if installErr != nil {
 				installFailed++
 				p.Error(fmt.Sprintf("%-45s %s", repo, installErr))
+				if entry.LogFile != "" {
+					p.Info(fmt.Sprintf("  Log: %s", entry.LogFile))
+				}
 			} else {
 				installed++
 			}
 
 			installBar.Increment()
-			_ = i
+			return installErr == nil
+		}, nil)
+		if schedErr != nil {
+			return fmt.Errorf("dependency graph error: %w", schedErr)
+		}
+		if ctx.Err() != nil {
+			spinner.Stop()
+			printInterrupted(p, nil, "flywork update")
+			return errInterrupted
 		}
 
 		installBar.Finish()
@@ -222,12 +329,16 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 		// ── Summary ─────────────────────────────────────────────────────────
 		elapsed := time.Since(overallStart).Truncate(time.Second)
-		p.SummaryBox("Update Complete", []string{
+		summaryLines := []string{
 			fmt.Sprintf("Pulled        %d", pulled),
 			fmt.Sprintf("Installed     %d", installed),
 			fmt.Sprintf("Failed        %d", pullFailed+installFailed),
 			fmt.Sprintf("Total time    %s", elapsed),
-		})
+		}
+		if len(logs.Counts()) > 0 {
+			summaryLines = append(summaryLines, fmt.Sprintf("Build logs    %s", logs.Dir()))
+		}
+		p.SummaryBox("Update Complete", summaryLines)
 	} else {
 		elapsed := time.Since(overallStart).Truncate(time.Second)
 		p.SummaryBox("Pull Complete", []string{
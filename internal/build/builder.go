@@ -15,15 +15,23 @@
 package build
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/cache"
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
+	"github.com/fireflyframework/fireflyframework-cli/internal/java"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/runlog"
 )
 
 // BuildOptions configures a DAG-aware build run.
@@ -33,35 +41,105 @@ type BuildOptions struct {
 	SkipTests   bool
 	ForceAll    bool     // Ignore change detection, rebuild everything
 	TargetRepos []string // Build specific repos + their dependents
+	UpTo        string   // Build a repo's changed upstream dependencies, then the repo
 	DryRun      bool     // Show plan without building
+	Jobs        int      // Repos built concurrently (< 2 = sequential)
+	HeapMB      int      // -Xmx budget per Maven build via MAVEN_OPTS (0 = Maven default)
+	HeapSet     bool     // HeapMB replaces an -Xmx already in MAVEN_OPTS (--mem-per-job)
+
+	// FailurePolicy decides what happens to the rest of the plan after a
+	// repo fails; the zero value skips the failed repo's dependents.
+	FailurePolicy dag.FailurePolicy
+
+	// Cache restores repos whose cache key has been built before instead of
+	// running Maven, and stores successful builds (nil = no build cache).
+	Cache *cache.Cache
+
+	// Incremental builds only the changed submodules of a multi-module repo
+	// whose own sources changed (mvn -pl <modules> -amd); see
+	// IncrementalModules. Ignored with ForceAll and Reactor.
+	Incremental bool
+
+	// Reactor builds all affected repos with a single multi-threaded Maven
+	// reactor (mvn -T Jobs) over a generated aggregator POM instead of one
+	// Maven invocation per repo.
+	Reactor bool
+
+	// Logs keeps the Maven output of every built repo, successful or not
+	// (nil = no logs).
+	Logs *runlog.Run
+
+	// Maven holds the global Maven settings (config plus --maven-arg); the
+	// overlay's settings of each repo are merged over them.
+	Maven maven.Settings
+
+	// OnOutput receives each line of Maven output as it is printed, with
+	// the repo that printed it ("reactor" for a reactor build). It is called
+	// concurrently when Jobs > 1.
+	OnOutput func(repo, line string)
 }
 
 // BuildResult holds the outcome of building a single repository.
 type BuildResult struct {
-	Repo    string
-	Skipped bool
-	Error   error
-	LogFile string
+	Repo      string
+	Skipped   bool
+	Cached    bool     // Restored from the build cache instead of built
+	Modules   []string // Submodules built incrementally (-pl ... -amd); nil for a full build
+	Blocked   bool     // Not built because BlockedBy failed
+	BlockedBy string   // Failed repo that blocked this one
+	// Interrupted is set when the build was cancelled before the repo
+	// finished; Error is then the context's error. Repos whose Maven build
+	// was running are recorded as interrupted in the manifest.
+	Interrupted bool
+	Error       error
+	LogFile     string
+	// Diagnosis is what Maven's output says went wrong: the failing module,
+	// compiler errors, failed tests, unresolved artifacts, enforcer
+	// violations and remediation hints. It is nil unless Maven failed.
+	Diagnosis *maven.Diagnosis
 }
 
-// BuildStartCallback is invoked before each repo build begins.
+// BuildStartCallback is invoked before each repo build begins. Callbacks are
+// never invoked concurrently, even when repos are built in parallel.
 type BuildStartCallback func(layer int, repo string, index int, total int)
 
-// BuildDoneCallback is invoked after each repo build completes.
+// BuildDoneCallback is invoked after each repo build completes, and for every
+// repo blocked by a failure or not started because the build was cancelled
+// (without a matching BuildStartCallback).
 type BuildDoneCallback func(layer int, repo string, index int, total int, result BuildResult)
 
 // RunDAGBuild executes a smart, DAG-aware build with change detection.
 //
 // Algorithm:
 //  1. Load the build manifest for change comparison
-//  2. Run DetectChanges to find repos with new commits
+//  2. Run DetectChanges to find repos with new commits or local edits
 //  3. Unless ForceAll, compute TransitiveClosure to get full build set
 //  4. If TargetRepos is set, scope to those repos + their transitive dependents
-//  5. Walk layers in order, building each repo via maven install
-//  6. Update manifest after each repo
-//  7. Save build logs on failure
-func RunDAGBuild(opts BuildOptions, onStart BuildStartCallback, onDone BuildDoneCallback) ([]BuildResult, [][]string, error) {
-	g := dag.FrameworkGraph()
+//  5. If UpTo is set, scope to that repo's transitive dependencies + the repo
+//  6. Schedule the affected subgraph, building up to Jobs repos at a time via
+//     maven install; a repo starts once its own dependencies are built. With
+//     a Cache, a repo whose cache key was built before is restored into ~/.m2
+//     instead, and successful builds are stored. With Reactor, the repos
+//     are built by one Maven reactor instead (see buildRun.reactor)
+//  7. Update manifest after each repo; repos blocked by a failure under the
+//     FailurePolicy are recorded as blocked rather than built
+//  8. Save build logs on failure
+//
+// Cancelling ctx terminates the running Maven builds and starts no new ones;
+// RunDAGBuild then returns the results together with the context's error.
+func RunDAGBuild(ctx context.Context, opts BuildOptions, onStart BuildStartCallback, onDone BuildDoneCallback) ([]BuildResult, [][]string, error) {
+	g, err := dag.Load(opts.ReposDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	overlay, err := dag.LoadOverlay(opts.ReposDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	cat, err := catalog.Load(opts.ReposDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	manifest, err := LoadManifest(DefaultManifestPath())
 	if err != nil {
@@ -73,6 +151,7 @@ func RunDAGBuild(opts BuildOptions, onStart BuildStartCallback, onDone BuildDone
 
 	// Determine which repos need building
 	var buildSet map[string]bool
+	var reasons map[string]string
 
 	if opts.ForceAll {
 		buildSet = make(map[string]bool)
@@ -80,7 +159,11 @@ func RunDAGBuild(opts BuildOptions, onStart BuildStartCallback, onDone BuildDone
 			buildSet[n] = true
 		}
 	} else {
-		changed := DetectChanges(g, opts.ReposDir, manifest)
+		reasons = ChangeReasons(g, opts.ReposDir, manifest)
+		changed := make(map[string]bool, len(reasons))
+		for repo := range reasons {
+			changed[repo] = true
+		}
 		buildSet = TransitiveClosure(g, changed)
 	}
 
@@ -108,6 +191,14 @@ func RunDAGBuild(opts BuildOptions, onStart BuildStartCallback, onDone BuildDone
 		buildSet = targeted
 	}
 
+	// If building up to a repo, scope to its changed upstream + the repo itself
+	if opts.UpTo != "" {
+		if !g.HasNode(opts.UpTo) {
+			return nil, nil, fmt.Errorf("unknown repository: %s", opts.UpTo)
+		}
+		buildSet = ScopeUpTo(g, opts.UpTo, buildSet)
+	}
+
 	// Build a subgraph of only the affected repos to get proper layer ordering
 	sub := g.Subgraph(buildSet)
 	layers, err := sub.Layers()
@@ -132,82 +223,275 @@ func RunDAGBuild(opts BuildOptions, onStart BuildStartCallback, onDone BuildDone
 		return results, layers, nil
 	}
 
-	results := make([]BuildResult, 0, total)
-	idx := 0
+	run := &buildRun{
+		ctx:      ctx,
+		opts:     opts,
+		overlay:  overlay,
+		cat:      cat,
+		manifest: manifest,
+		reasons:  reasons,
+		total:    total,
+		results:  make([]BuildResult, total),
+		onStart:  onStart,
+		onDone:   onDone,
+	}
+	if opts.Cache != nil {
+		run.jdk = java.RuntimeVersion(opts.JavaHome)
+		run.cacheKeys = CacheKeys(g, opts.ReposDir, run.jdk, sub.Nodes(), run.inputs)
+	}
 
-	for layerIdx, layer := range layers {
-		for _, repo := range layer {
-			idx++
-			dir := filepath.Join(opts.ReposDir, repo)
+	if opts.Reactor {
+		if err := run.reactor(sub, layers); err != nil {
+			return nil, nil, err
+		}
+		return run.results, layers, ctx.Err()
+	}
 
-			if onStart != nil {
-				onStart(layerIdx, repo, idx, total)
-			}
+	err = dag.Schedule(sub, opts.Jobs, opts.FailurePolicy, func(layerIdx int, repo string, idx int) bool {
+		dir := filepath.Join(opts.ReposDir, repo)
+		if ctx.Err() != nil {
+			run.finish(layerIdx, idx, BuildResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return false
+		}
+		run.start(layerIdx, repo, idx)
 
-			// Skip repos that have no pom.xml
-			var buildErr error
-			var buildOutput []byte
-			pomPath := filepath.Join(dir, "pom.xml")
-			if _, serr := os.Stat(pomPath); os.IsNotExist(serr) {
-				r := BuildResult{Repo: repo, Skipped: true}
-				results = append(results, r)
-				if onDone != nil {
-					onDone(layerIdx, repo, idx, total, r)
-				}
-				continue
-			}
+		if run.skip(repo) {
+			run.finish(layerIdx, idx, BuildResult{Repo: repo, Skipped: true})
+			return true
+		}
 
-			sha, _ := git.HeadSHA(dir)
+		sha, _ := git.HeadSHA(dir)
+		fingerprint, _ := Fingerprint(dir)
+		if run.restore(repo, sha, fingerprint) {
+			run.finish(layerIdx, idx, BuildResult{Repo: repo, Cached: true})
+			return true
+		}
 
-			if opts.JavaHome != "" {
-				buildOutput, buildErr = maven.InstallQuietWithJavaOutput(dir, opts.JavaHome, opts.SkipTests)
-			} else {
-				buildOutput, buildErr = maven.InstallQuietOutput(dir, opts.SkipTests)
-			}
+		started := time.Now()
+		settings := overlay.Settings(repo)
+		skipTests := settings.ResolveSkipTests(opts.SkipTests)
+		var args []string
+		modules := run.incremental(sub, repo)
+		if len(modules) > 0 {
+			args = []string{"-pl", strings.Join(modules, ","), "-amd"}
+		}
+		buildOutput, buildErr := maven.InstallWithOptions(ctx, dir, run.runOptions(repo), skipTests, args...)
+		if buildErr != nil && ctx.Err() != nil {
+			opts.Logs.Record(repo, ctx.Err())
+			run.manifest.MarkInterrupted(repo, sha)
+			run.finish(layerIdx, idx, BuildResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return false
+		}
+		run.record(repo, sha, fingerprint, buildErr, time.Since(started))
 
-			if buildErr != nil {
-				manifest.MarkFailed(repo, sha, buildErr)
-			} else {
-				manifest.MarkSuccess(repo, sha)
-			}
+		logFile := opts.Logs.Record(repo, buildErr)
+		var diagnosis *maven.Diagnosis
+		if buildErr != nil && len(buildOutput) > 0 {
+			diagnosis = diagnose(dir, repo, buildOutput)
+		}
 
-			// Write build log on failure
-			var logFile string
-			if buildErr != nil && len(buildOutput) > 0 {
-				logFile = writeBuildLog(repo, buildOutput)
-			}
+		run.finish(layerIdx, idx, BuildResult{Repo: repo, Modules: modules, Error: buildErr, LogFile: logFile, Diagnosis: diagnosis})
+		return buildErr == nil
+	}, func(layerIdx int, repo string, idx int, blockedBy string) {
+		if ctx.Err() != nil {
+			run.finish(layerIdx, idx, BuildResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return
+		}
+		run.manifest.MarkBlocked(repo, blockedBy)
+		run.finish(layerIdx, idx, BuildResult{Repo: repo, Blocked: true, BlockedBy: blockedBy})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
-			r := BuildResult{Repo: repo, Error: buildErr, LogFile: logFile}
-			results = append(results, r)
-			_ = manifest.Save()
+	return run.results, layers, ctx.Err()
+}
 
-			if onDone != nil {
-				onDone(layerIdx, repo, idx, total, r)
-			}
-		}
+// buildRun is the state shared by the repo builds of one RunDAGBuild. Its
+// methods may be called from concurrent repo builds.
+type buildRun struct {
+	ctx       context.Context
+	opts      BuildOptions
+	overlay   *dag.Overlay
+	cat       *catalog.Catalog
+	manifest  *BuildManifest
+	reasons   map[string]string // repo → why it changed; nil with ForceAll
+	cacheKeys map[string]string // repo → build cache key, when caching
+	jdk       string
+	total     int
+	results   []BuildResult
+	onStart   BuildStartCallback
+	onDone    BuildDoneCallback
+
+	mu sync.Mutex // serializes callbacks and manifest saves
+}
+
+// start reports that a repo's build has begun.
+func (r *buildRun) start(layer int, repo string, idx int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.onStart != nil {
+		r.onStart(layer, repo, idx, r.total)
+	}
+}
+
+// finish stores the result of the idx-th repo of the plan, saves the
+// manifest and reports the result.
+func (r *buildRun) finish(layer, idx int, res BuildResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results[idx-1] = res
+	if !res.Skipped {
+		_ = r.manifest.Save()
+	}
+	if r.onDone != nil {
+		r.onDone(layer, res.Repo, idx, r.total, res)
+	}
+}
+
+// skip reports whether a repo is not built: it has no pom.xml, is excluded
+// by the overlay or is not a Maven repo in the catalog.
+func (r *buildRun) skip(repo string) bool {
+	pomPath := filepath.Join(r.opts.ReposDir, repo, "pom.xml")
+	_, err := os.Stat(pomPath)
+	return os.IsNotExist(err) || r.overlay.Settings(repo).Skip || !r.cat.Buildable(repo)
+}
+
+// restore restores a repo from the build cache and records it as built,
+// reporting whether its cache key was found.
+func (r *buildRun) restore(repo, sha, fingerprint string) bool {
+	key := r.cacheKeys[repo]
+	if key == "" {
+		return false
+	}
+	entry, err := r.opts.Cache.Restore(r.ctx, key)
+	if err != nil {
+		return false
+	}
+	r.manifest.MarkSuccess(repo, sha, fingerprint)
+	r.manifest.RecordBuildConfig(repo, r.buildConfig(repo))
+	r.manifest.RecordArtifacts(repo, entry.Artifacts)
+	return true
+}
+
+// runOptions returns the Maven options of the build of repo: the JDK and
+// heap budget of the run, the global Maven settings with the repo's merged
+// over them, and the output streamed to the repo's log and OnOutput.
+func (r *buildRun) runOptions(repo string) maven.RunOptions {
+	runOpts := maven.RunOptions{
+		JavaHome: r.opts.JavaHome,
+		HeapMB:   r.opts.HeapMB,
+		HeapSet:  r.opts.HeapSet,
+		Maven:    r.mavenSettings(repo),
+		LogFile:  r.opts.Logs.Path(repo),
+	}
+	if r.opts.OnOutput != nil {
+		runOpts.Tail = func(line string) { r.opts.OnOutput(repo, line) }
 	}
+	return runOpts
+}
 
-	return results, layers, nil
+// mavenSettings returns the effective Maven settings of a repo's build: the
+// global settings with the repo's overlay settings merged over them. A
+// reactor build only applies the global settings.
+func (r *buildRun) mavenSettings(repo string) maven.Settings {
+	if r.opts.Reactor {
+		return r.opts.Maven
+	}
+	return r.opts.Maven.Merge(r.overlay.Settings(repo).Maven())
 }
 
-// LogsDir returns the path to the build logs directory (~/.flywork/logs).
-func LogsDir() string {
-	return filepath.Join(config.FlyworkHome(), "logs")
+// inputs returns the settings besides its sources that determine a repo's
+// build output: the test mode and the effective Maven settings.
+func (r *buildRun) inputs(repo string) []string {
+	settings := r.overlay.Settings(repo)
+	skipTests := settings.ResolveSkipTests(r.opts.SkipTests)
+	return append([]string{fmt.Sprintf("skip-tests=%v", skipTests)}, r.mavenSettings(repo).Inputs()...)
 }
 
-// writeBuildLog writes Maven output to ~/.flywork/logs/<repo>.log.
-func writeBuildLog(repo string, output []byte) string {
-	logsDir := LogsDir()
-	if err := os.MkdirAll(logsDir, 0755); err != nil {
-		return ""
+// buildConfig hashes a repo's inputs for the manifest.
+func (r *buildRun) buildConfig(repo string) string {
+	sum := sha256.Sum256([]byte(strings.Join(r.inputs(repo), "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// incremental returns the submodules to build with -pl ... -amd, or nil for
+// a full build. Only repos whose own sources changed since a clean,
+// successful build with the same configuration qualify; a repo with a
+// dependency in the plan is rebuilt in full against the new upstream
+// artifacts.
+func (r *buildRun) incremental(sub *dag.Graph, repo string) []string {
+	if !r.opts.Incremental || r.opts.Reactor || r.reasons[repo] != ReasonSourcesChanged {
+		return nil
+	}
+	if len(sub.DependenciesOf(repo)) > 0 {
+		return nil
 	}
-	logFile := filepath.Join(logsDir, repo+".log")
+	if strings.Contains(r.manifest.LastFingerprint(repo), dirtySeparator) ||
+		r.manifest.LastBuildConfig(repo) != r.buildConfig(repo) {
+		return nil
+	}
+	modules, _ := IncrementalModules(filepath.Join(r.opts.ReposDir, repo), r.manifest.LastSHA(repo))
+	return modules
+}
 
-	header := fmt.Sprintf("=== Build log for %s ===\n=== %s ===\n\n", repo, time.Now().Format(time.RFC3339))
-	content := append([]byte(header), output...)
+// record updates the manifest with the outcome of a repo's Maven build and
+// stores successful builds in the build cache. A build whose goals do not
+// install is only recorded as verified: ~/.m2 holds older artifacts, which
+// must neither mark the repo up to date nor be cached under its new key.
+func (r *buildRun) record(repo, sha, fingerprint string, buildErr error, d time.Duration) {
+	switch {
+	case buildErr != nil:
+		r.manifest.MarkFailed(repo, sha, buildErr)
+	case !r.mavenSettings(repo).Installs():
+		r.manifest.MarkVerified(repo, sha)
+	default:
+		r.manifest.MarkSuccess(repo, sha, fingerprint)
+		r.manifest.RecordBuildConfig(repo, r.buildConfig(repo))
+		dir := filepath.Join(r.opts.ReposDir, repo)
+		if artifacts, err := maven.InstalledArtifacts(dir); err == nil {
+			r.manifest.RecordArtifacts(repo, artifacts)
+			if key := r.cacheKeys[repo]; key != "" {
+				storeBuild(r.ctx, r.opts.Cache, cache.Entry{Key: key, Repo: repo, Fingerprint: fingerprint, JDK: r.jdk}, artifacts)
+			}
+		}
+	}
+	r.manifest.RecordDuration(repo, d)
+}
 
-	if err := os.WriteFile(logFile, content, 0644); err != nil {
-		return ""
+// storeBuild saves the installed artifacts of a successful build in the
+// cache under e.Key. Only artifacts found in ~/.m2 are stored; failures to
+// store are ignored, as the build itself succeeded. Cancelling ctx aborts
+// the upload.
+func storeBuild(ctx context.Context, c *cache.Cache, e cache.Entry, artifacts []maven.Artifact) {
+	var installed []maven.Artifact
+	for _, a := range artifacts {
+		if a.SHA256 != "" {
+			installed = append(installed, a)
+		}
+	}
+	if len(installed) == 0 {
+		return
+	}
+	e.CreatedAt = time.Now()
+	e.Artifacts = installed
+	_ = c.Store(ctx, e)
+}
+
+// diagnose analyzes the output of a failed Maven build of repo in dir,
+// showing compiler error paths relative to dir and naming repo in the hints.
+func diagnose(dir, repo string, output []byte) *maven.Diagnosis {
+	d := maven.Analyze(output)
+	if d == nil {
+		return nil
+	}
+	for i, e := range d.CompilerErrors {
+		if rel, err := filepath.Rel(dir, e.File); err == nil && !strings.HasPrefix(rel, "..") {
+			d.CompilerErrors[i].File = rel
+		}
+	}
+	for i, h := range d.Hints {
+		d.Hints[i] = strings.ReplaceAll(h, "<repo>", repo)
 	}
-	return logFile
+	return d
 }
@@ -0,0 +1,97 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+)
+
+// cacheKeyVersion is mixed into every cache key; bump it when the key inputs
+// or the entry layout change.
+const cacheKeyVersion = "flywork-cache-v1"
+
+// CacheKeys computes build cache keys for repos. A repo's key hashes its
+// working-tree fingerprint, the JDK, the repo's build inputs returned by
+// inputs (Maven arguments, test mode) and the keys of its direct
+// dependencies in g, so a change anywhere upstream changes the key of every
+// dependent. Repos whose fingerprint cannot be computed, or that depend on
+// such a repo, get no key and are never cached; dependencies that are not
+// cloned contribute a fixed marker.
+func CacheKeys(g *dag.Graph, reposDir, jdk string, repos []string, inputs func(repo string) []string) map[string]string {
+	keys := make(map[string]string)
+	visiting := make(map[string]bool)
+
+	var key func(repo string) string
+	key = func(repo string) string {
+		if k, ok := keys[repo]; ok {
+			return k
+		}
+		if visiting[repo] {
+			return "" // cycle
+		}
+		visiting[repo] = true
+		defer delete(visiting, repo)
+
+		// A dependency that is not cloned is resolved from remote
+		// repositories, like it is by Maven, rather than built.
+		dir := filepath.Join(reposDir, repo)
+		fingerprint := "not-cloned"
+		if _, err := os.Stat(dir); err == nil {
+			if fingerprint, err = Fingerprint(dir); err != nil {
+				keys[repo] = ""
+				return ""
+			}
+		}
+
+		deps := g.DependenciesOf(repo)
+		sort.Strings(deps)
+		h := sha256.New()
+		write := func(s string) {
+			h.Write([]byte(s))
+			h.Write([]byte{0})
+		}
+		write(cacheKeyVersion)
+		write(repo)
+		write(fingerprint)
+		write(jdk)
+		for _, in := range inputs(repo) {
+			write(in)
+		}
+		for _, dep := range deps {
+			depKey := key(dep)
+			if depKey == "" {
+				keys[repo] = ""
+				return ""
+			}
+			write(dep + "=" + depKey)
+		}
+		keys[repo] = hex.EncodeToString(h.Sum(nil))
+		return keys[repo]
+	}
+
+	out := make(map[string]string, len(repos))
+	for _, repo := range repos {
+		if k := key(repo); k != "" {
+			out[repo] = k
+		}
+	}
+	return out
+}
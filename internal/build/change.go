@@ -19,14 +19,31 @@ import (
 	"path/filepath"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
-	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 )
 
-// DetectChanges compares the current HEAD SHA of each repo in the graph against
-// the last successfully built SHA recorded in the manifest. Repos whose SHA
-// differs (or that have no manifest entry) are marked as changed.
+// ReasonSourcesChanged is the change reason of a repo whose working tree
+// differs from its last successful build.
+const ReasonSourcesChanged = "sources changed"
+
+// DetectChanges compares the working-tree fingerprint of each repo in the
+// graph (HEAD plus uncommitted and untracked edits, see Fingerprint) against
+// the fingerprint of its last successful build recorded in the manifest.
+// Repos whose fingerprint differs (or that have no manifest entry) are marked
+// as changed, so a local edit triggers a rebuild and a no-op does not. Repos
+// whose installed artifacts are missing from ~/.m2 or no longer match the
+// recorded checksums are marked as changed too.
 func DetectChanges(g *dag.Graph, reposDir string, manifest *BuildManifest) map[string]bool {
 	changed := make(map[string]bool)
+	for repo := range ChangeReasons(g, reposDir, manifest) {
+		changed[repo] = true
+	}
+	return changed
+}
+
+// ChangeReasons is DetectChanges with the reason each repo is considered
+// changed.
+func ChangeReasons(g *dag.Graph, reposDir string, manifest *BuildManifest) map[string]string {
+	changed := make(map[string]string)
 
 	for _, repo := range g.Nodes() {
 		dir := filepath.Join(reposDir, repo)
@@ -36,22 +53,53 @@ func DetectChanges(g *dag.Graph, reposDir string, manifest *BuildManifest) map[s
 			continue
 		}
 
-		currentSHA, err := git.HeadSHA(dir)
+		current, err := Fingerprint(dir)
 		if err != nil {
-			// Can't read SHA — treat as changed
-			changed[repo] = true
+			// Can't read the working tree — treat as changed
+			changed[repo] = "cannot read working tree"
 			continue
 		}
 
-		lastSHA := manifest.LastSHA(repo)
-		if lastSHA == "" || lastSHA != currentSHA {
-			changed[repo] = true
+		last := manifest.LastFingerprint(repo)
+		switch {
+		case last == "" && manifest.Status(repo) == "interrupted":
+			changed[repo] = "last build was interrupted"
+			continue
+		case last == "" && manifest.Status(repo) == "verified":
+			changed[repo] = "last build did not install its artifacts"
+			continue
+		case last == "":
+			changed[repo] = "no successful build recorded"
+			continue
+		case last != current:
+			changed[repo] = ReasonSourcesChanged
+			continue
+		}
+
+		for _, a := range manifest.Artifacts(repo) {
+			if err := a.Verify(); err != nil {
+				changed[repo] = err.Error()
+				break
+			}
 		}
 	}
 
 	return changed
 }
 
+// ScopeUpTo restricts a build set to the transitive dependencies of repo and
+// always includes repo itself, so that the repo can be built on top of fresh
+// upstream artifacts.
+func ScopeUpTo(g *dag.Graph, repo string, buildSet map[string]bool) map[string]bool {
+	scoped := map[string]bool{repo: true}
+	for _, dep := range g.TransitiveDependenciesOf(repo) {
+		if buildSet[dep] {
+			scoped[dep] = true
+		}
+	}
+	return scoped
+}
+
 // TransitiveClosure expands a set of directly changed repos to include all
 // downstream dependents. For each changed repo, it walks the reverse edges of
 // the DAG via BFS to find every repo that transitively depends on the change.
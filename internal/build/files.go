@@ -0,0 +1,198 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+	"github.com/fireflyframework/fireflyframework-cli/internal/git"
+)
+
+// DefaultIgnoreGlobs lists the files that never make a repo changed on their
+// own: documentation and CI configuration.
+var DefaultIgnoreGlobs = []string{"*.md", "docs/**", ".github/**"}
+
+// FileChanges maps changed file paths to the repositories that contain them.
+type FileChanges struct {
+	// Repos maps each changed repo to its relevant changed files, relative
+	// to the repo root.
+	Repos map[string][]string
+	// Ignored lists the changed files that matched an ignore glob.
+	Ignored []string
+	// Unmatched lists the changed files outside every known repo.
+	Unmatched []string
+	// Errors maps repos whose changes could not be listed to the reason.
+	Errors map[string]string
+}
+
+// Changed returns the set of changed repos.
+func (fc *FileChanges) Changed() map[string]bool {
+	out := make(map[string]bool, len(fc.Repos))
+	for repo := range fc.Repos {
+		out[repo] = true
+	}
+	return out
+}
+
+func newFileChanges() *FileChanges {
+	return &FileChanges{
+		Repos:     make(map[string][]string),
+		Ignored:   []string{},
+		Unmatched: []string{},
+		Errors:    make(map[string]string),
+	}
+}
+
+// MapChangedFiles attributes changed file paths to the repos of g. Paths are
+// either absolute or relative to reposDir, so their first component is the
+// repo directory name. Files matching an ignore glob are set aside.
+func MapChangedFiles(g *dag.Graph, reposDir string, files []string, ignore []string) *FileChanges {
+	fc := newFileChanges()
+	matchers := compileGlobs(ignore)
+	absRoot, _ := filepath.Abs(reposDir)
+
+	for _, f := range files {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		rel := f
+		if filepath.IsAbs(f) {
+			r, err := filepath.Rel(absRoot, f)
+			if err != nil || strings.HasPrefix(r, "..") {
+				fc.Unmatched = append(fc.Unmatched, f)
+				continue
+			}
+			rel = r
+		}
+		rel = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(rel)), "./")
+
+		repo, inRepo, _ := strings.Cut(rel, "/")
+		if !g.HasNode(repo) {
+			fc.Unmatched = append(fc.Unmatched, f)
+			continue
+		}
+		fc.add(repo, inRepo, f, matchers)
+	}
+	return fc
+}
+
+// ChangedFilesSince lists, for every cloned repo of g, the files changed
+// between ref and HEAD. Repos where ref cannot be resolved are recorded in
+// Errors and treated as changed, so that nothing is silently left out.
+func ChangedFilesSince(g *dag.Graph, reposDir, ref string, ignore []string) *FileChanges {
+	fc := newFileChanges()
+	matchers := compileGlobs(ignore)
+
+	for _, repo := range g.Nodes() {
+		dir := filepath.Join(reposDir, repo)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		files, err := git.DiffStatSince(dir, ref)
+		if err != nil {
+			fc.Errors[repo] = fmt.Sprintf("cannot diff against %s: %v", ref, err)
+			fc.Repos[repo] = []string{}
+			continue
+		}
+		for _, f := range files {
+			fc.add(repo, f, path.Join(repo, f), matchers)
+		}
+	}
+	return fc
+}
+
+func (fc *FileChanges) add(repo, inRepo, original string, matchers []*regexp.Regexp) {
+	if inRepo != "" && matchesAny(matchers, inRepo) {
+		fc.Ignored = append(fc.Ignored, original)
+		return
+	}
+	fc.Repos[repo] = append(fc.Repos[repo], inRepo)
+}
+
+// SortedRepos returns the changed repos sorted by name.
+func (fc *FileChanges) SortedRepos() []string {
+	out := make([]string, 0, len(fc.Repos))
+	for repo := range fc.Repos {
+		out = append(out, repo)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// compileGlobs turns gitignore-style globs into regular expressions. A glob
+// without a slash (apart from a trailing "/" or "/**") matches at any depth,
+// so "*.md" and "docs/**" also cover submodules; otherwise it matches the
+// path from the repo root. "**" spans directories.
+func compileGlobs(globs []string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		glob = strings.TrimSpace(glob)
+		if glob == "" {
+			continue
+		}
+		if strings.HasSuffix(glob, "/") {
+			glob += "**"
+		}
+		anchored := strings.Contains(strings.TrimSuffix(glob, "/**"), "/") || strings.HasPrefix(glob, "**")
+		glob = strings.TrimPrefix(glob, "/")
+
+		var b strings.Builder
+		b.WriteString("^")
+		if !anchored {
+			b.WriteString("(?:.*/)?")
+		}
+		for i := 0; i < len(glob); i++ {
+			c := glob[i]
+			switch {
+			case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			case c == '*':
+				b.WriteString("[^/]*")
+			case c == '?':
+				b.WriteString("[^/]")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		b.WriteString("$")
+		if re, err := regexp.Compile(b.String()); err == nil {
+			out = append(out, re)
+		}
+	}
+	return out
+}
+
+func matchesAny(matchers []*regexp.Regexp, p string) bool {
+	for _, re := range matchers {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
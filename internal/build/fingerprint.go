@@ -0,0 +1,100 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/git"
+)
+
+// dirtySeparator joins HEAD and the working-tree hash in a fingerprint.
+const dirtySeparator = "+dirty."
+
+// Fingerprint identifies the sources of a repo's working tree: the HEAD SHA,
+// followed by a content hash of every uncommitted tracked file and every
+// untracked file not excluded by .gitignore. Files under a target/ directory
+// are left out, so Maven output never changes the fingerprint. A clean tree's
+// fingerprint is the HEAD SHA itself.
+func Fingerprint(dir string) (string, error) {
+	sha, err := git.HeadSHA(dir)
+	if err != nil {
+		return "", err
+	}
+	files, err := git.WorkingTreeChanges(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var relevant []string
+	for _, f := range files {
+		if !isBuildOutput(f) {
+			relevant = append(relevant, f)
+		}
+	}
+	if len(relevant) == 0 {
+		return sha, nil
+	}
+	sort.Strings(relevant)
+
+	h := sha256.New()
+	for _, f := range relevant {
+		h.Write([]byte(f))
+		h.Write([]byte{0})
+		if err := hashFile(h, filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+			// Deleted (or unreadable) files still change the fingerprint
+			// through their path.
+			h.Write([]byte("<deleted>"))
+		}
+		h.Write([]byte{0})
+	}
+	return sha + dirtySeparator + hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// isBuildOutput reports whether a repo-relative path lies in a target/
+// directory.
+func isBuildOutput(p string) bool {
+	for _, part := range strings.Split(p, "/") {
+		if part == "target" {
+			return true
+		}
+	}
+	return false
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		// Submodules and nested repositories show up as directories.
+		_, err = io.WriteString(w, "<dir>")
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
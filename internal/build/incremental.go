@@ -0,0 +1,112 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/git"
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/version"
+)
+
+// buildConfigFiles are repo-root files that change how every module is
+// built, so a change to any of them requires a full build.
+var buildConfigFiles = []string{"/pom.xml", "/mvnw", "/mvnw.cmd", "/.mvn/**"}
+
+// IncrementalModules returns the submodules of a multi-module repo that
+// changed since lastSHA, the commit of its last successful build, including
+// uncommitted and untracked edits. They are built with
+// mvn -pl <modules> -amd, which also rebuilds the modules that depend on
+// them. When the repo has to be built in full — it has no submodules, the
+// root pom.xml or build configuration (.mvn/, mvnw) changed, files outside
+// every submodule other than documentation changed, or the changes cannot be
+// listed — modules is nil and reason says why.
+func IncrementalModules(dir, lastSHA string) (modules []string, reason string) {
+	if lastSHA == "" {
+		return nil, "no previous build to compare with"
+	}
+
+	// Submodule directories: those the poms declare, at any depth, and the
+	// top-level directories version.FindAllPoms discovers.
+	submodules := make(map[string]bool)
+	declared, _ := maven.ModuleDirs(dir)
+	for _, rel := range declared {
+		submodules[rel] = true
+	}
+	for _, pom := range version.FindAllPoms(dir) {
+		rel, err := filepath.Rel(dir, filepath.Dir(pom))
+		if err == nil && rel != "." {
+			submodules[filepath.ToSlash(rel)] = true
+		}
+	}
+	if len(submodules) == 0 {
+		return nil, "single-module repo"
+	}
+
+	committed, err := git.DiffStatSince(dir, lastSHA)
+	if err != nil {
+		return nil, "cannot diff against the last build"
+	}
+	uncommitted, err := git.WorkingTreeChanges(dir)
+	if err != nil {
+		return nil, "cannot read working tree"
+	}
+
+	config := compileGlobs(buildConfigFiles)
+	docs := compileGlobs(DefaultIgnoreGlobs)
+	changed := make(map[string]bool)
+	for _, f := range append(committed, uncommitted...) {
+		f = filepath.ToSlash(f)
+		if f == "" || isBuildOutput(f) {
+			continue
+		}
+		if matchesAny(config, f) {
+			return nil, f + " changed"
+		}
+		module, found := moduleOf(submodules, f)
+		switch {
+		case found:
+			changed[module] = true
+		case matchesAny(docs, f):
+			// Documentation outside the modules builds nothing.
+		default:
+			return nil, f + " is outside every submodule"
+		}
+	}
+	if len(changed) == 0 {
+		return nil, "no submodule changed"
+	}
+
+	for m := range changed {
+		modules = append(modules, m)
+	}
+	sort.Strings(modules)
+	return modules, ""
+}
+
+// moduleOf returns the innermost submodule containing the repo-relative
+// path f, so that a change in modules/foo/src is attributed to modules/foo
+// even when modules itself is not a module.
+func moduleOf(submodules map[string]bool, f string) (string, bool) {
+	for dir := path.Dir(f); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if submodules[dir] {
+			return dir, true
+		}
+	}
+	return "", false
+}
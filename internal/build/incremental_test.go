@@ -0,0 +1,48 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import "testing"
+
+func TestModuleOf(t *testing.T) {
+	submodules := map[string]bool{
+		"core":             true,
+		"modules/foo":      true,
+		"modules/foo/impl": true,
+		"modules/bar":      true,
+	}
+	tests := []struct {
+		file   string
+		module string
+		found  bool
+	}{
+		{"core/src/main/java/A.java", "core", true},
+		{"core/pom.xml", "core", true},
+		{"modules/foo/src/A.java", "modules/foo", true},
+		{"modules/foo/impl/src/A.java", "modules/foo/impl", true},
+		{"modules/bar/pom.xml", "modules/bar", true},
+		{"modules/baz/src/A.java", "", false},
+		{"modules/README.md", "", false},
+		{"corex/src/A.java", "", false},
+		{"pom.xml", "", false},
+		{"core", "", false},
+	}
+	for _, tt := range tests {
+		module, found := moduleOf(submodules, tt.file)
+		if module != tt.module || found != tt.found {
+			t.Errorf("moduleOf(%q) = %q, %v, want %q, %v", tt.file, module, found, tt.module, tt.found)
+		}
+	}
+}
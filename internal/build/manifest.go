@@ -22,9 +22,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
 )
 
 const (
@@ -32,22 +34,32 @@ const (
 	ManifestVer  = 1
 )
 
-// BuildManifest persists build state across invocations.
+// BuildManifest persists build state across invocations. Its methods are safe
+// for concurrent use.
 type BuildManifest struct {
 	Version   int                    `json:"version"`
 	UpdatedAt time.Time              `json:"updated_at"`
 	Repos     map[string]*BuildState `json:"repos"`
 
 	path string
+	mu   sync.Mutex // guards Repos while builds run concurrently
 }
 
 // BuildState tracks the last build result for a single repository.
 type BuildState struct {
 	LastBuildSHA    string    `json:"last_build_sha"`
+	Fingerprint     string    `json:"fingerprint,omitempty"` // HEAD plus a hash of uncommitted changes; see Fingerprint
 	LastBuildTime   time.Time `json:"last_build_time"`
 	ArtifactVersion string    `json:"artifact_version,omitempty"`
-	Status          string    `json:"status"` // pending, success, failed
-	Error           string    `json:"error,omitempty"`
+	// Artifacts lists the modules installed into ~/.m2 by the last
+	// successful build, with the checksums of the installed files.
+	Artifacts []maven.Artifact `json:"artifacts,omitempty"`
+	// BuildConfig hashes the Maven arguments and test mode of the last
+	// successful build; incremental builds require it to be unchanged.
+	BuildConfig string `json:"build_config,omitempty"`
+	Status      string `json:"status"` // pending, success, verified, failed, blocked, interrupted
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms,omitempty"` // wall time of the last Maven build
 }
 
 // DefaultManifestPath returns ~/.flywork/build-manifest.json.
@@ -88,6 +100,8 @@ func LoadManifest(path string) (*BuildManifest, error) {
 
 // Save writes the manifest to disk.
 func (m *BuildManifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.path == "" {
 		m.path = DefaultManifestPath()
 	}
@@ -99,7 +113,7 @@ func (m *BuildManifest) Save() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(m.path, data, 0644)
+	return config.WriteFileAtomic(m.path, data, 0644)
 }
 
 // SetPath overrides the file path for this manifest.
@@ -108,30 +122,101 @@ func (m *BuildManifest) SetPath(p string) {
 }
 
 // LastSHA returns the last successfully built SHA for a repo, or "" if unknown.
-// Only returns a SHA if the last build was successful — failed builds are
-// always retried regardless of whether the SHA has changed.
+// Only returns a SHA if the last build was successful — failed, blocked,
+// interrupted and verify-only builds are always retried regardless of whether
+// the SHA has changed.
 func (m *BuildManifest) LastSHA(repo string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	bs, ok := m.Repos[repo]
-	if !ok {
+	if !ok || retried(bs.Status) {
 		return ""
 	}
-	if bs.Status == "failed" {
+	return bs.LastBuildSHA
+}
+
+// LastFingerprint returns the working-tree fingerprint of the last successful
+// build of a repo, or "" if unknown. Manifests written before fingerprints
+// were recorded fall back to the SHA, which is the fingerprint of a clean
+// tree.
+func (m *BuildManifest) LastFingerprint(repo string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs, ok := m.Repos[repo]
+	if !ok || retried(bs.Status) {
 		return ""
 	}
-	return bs.LastBuildSHA
+	if bs.Fingerprint == "" {
+		return bs.LastBuildSHA
+	}
+	return bs.Fingerprint
 }
 
-// MarkSuccess records a successful build for a repo.
-func (m *BuildManifest) MarkSuccess(repo, sha string) {
+// MarkSuccess records a successful build of a repo at HEAD sha with the
+// given working-tree fingerprint.
+func (m *BuildManifest) MarkSuccess(repo, sha, fingerprint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	bs := m.ensureState(repo)
 	bs.LastBuildSHA = sha
+	bs.Fingerprint = fingerprint
 	bs.LastBuildTime = time.Now()
 	bs.Status = "success"
 	bs.Error = ""
 }
 
+// MarkVerified records a successful build of a repo at HEAD sha whose goals
+// did not install its artifacts (e.g. clean verify). ~/.m2 still holds the
+// artifacts of an earlier build, so the repo is not up to date and its
+// recorded artifacts are dropped.
+func (m *BuildManifest) MarkVerified(repo, sha string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs := m.ensureState(repo)
+	bs.LastBuildSHA = sha
+	bs.LastBuildTime = time.Now()
+	bs.Status = "verified"
+	bs.Error = ""
+	bs.Artifacts = nil
+	bs.ArtifactVersion = ""
+}
+
+// RecordArtifacts stores the artifacts installed by the last successful build
+// of a repo; the version of its root module becomes the ArtifactVersion.
+// Modules without a checksum were not installed (e.g. install is skipped for
+// them) and are not recorded.
+func (m *BuildManifest) RecordArtifacts(repo string, artifacts []maven.Artifact) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs := m.ensureState(repo)
+	bs.Artifacts = nil
+	bs.ArtifactVersion = ""
+	if len(artifacts) > 0 {
+		bs.ArtifactVersion = artifacts[0].Version
+	}
+	for _, a := range artifacts {
+		if a.SHA256 != "" {
+			bs.Artifacts = append(bs.Artifacts, a)
+		}
+	}
+}
+
+// Artifacts returns the artifacts recorded for a repo's last successful
+// build.
+func (m *BuildManifest) Artifacts(repo string) []maven.Artifact {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs, ok := m.Repos[repo]
+	if !ok {
+		return nil
+	}
+	return append([]maven.Artifact(nil), bs.Artifacts...)
+}
+
 // MarkFailed records a failed build for a repo.
 func (m *BuildManifest) MarkFailed(repo, sha string, buildErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	bs := m.ensureState(repo)
 	bs.LastBuildSHA = sha
 	bs.LastBuildTime = time.Now()
@@ -141,6 +226,88 @@ func (m *BuildManifest) MarkFailed(repo, sha string, buildErr error) {
 	}
 }
 
+// MarkBlocked records that a repo was not built because blockedBy, one of
+// its dependencies, failed. The last successful SHA is kept for reference but
+// no longer counts as up to date.
+func (m *BuildManifest) MarkBlocked(repo, blockedBy string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs := m.ensureState(repo)
+	bs.LastBuildTime = time.Now()
+	bs.Status = "blocked"
+	bs.Error = "blocked by failed dependency " + blockedBy
+}
+
+// MarkInterrupted records that a repo's build was cancelled while it was
+// running, e.g. by Ctrl-C. Like a failed build, it is retried by the next
+// build.
+func (m *BuildManifest) MarkInterrupted(repo, sha string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs := m.ensureState(repo)
+	bs.LastBuildSHA = sha
+	bs.LastBuildTime = time.Now()
+	bs.Status = "interrupted"
+	bs.Error = "build interrupted"
+}
+
+// Status returns the status of a repo's last build, or "" if it has never
+// been built.
+func (m *BuildManifest) Status(repo string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if bs, ok := m.Repos[repo]; ok {
+		return bs.Status
+	}
+	return ""
+}
+
+// RecordBuildConfig stores the build configuration hash of a repo's last
+// successful build.
+func (m *BuildManifest) RecordBuildConfig(repo, hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ensureState(repo).BuildConfig = hash
+}
+
+// LastBuildConfig returns the build configuration hash recorded for a repo's
+// last successful build, or "" if unknown.
+func (m *BuildManifest) LastBuildConfig(repo string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs, ok := m.Repos[repo]
+	if !ok || bs.Status != "success" {
+		return ""
+	}
+	return bs.BuildConfig
+}
+
+// RecordDuration stores how long the last Maven build of a repo took.
+func (m *BuildManifest) RecordDuration(repo string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ensureState(repo).DurationMs = d.Milliseconds()
+}
+
+// Durations returns the last recorded build duration of every repo that has one.
+func (m *BuildManifest) Durations() map[string]time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]time.Duration, len(m.Repos))
+	for repo, bs := range m.Repos {
+		if bs.DurationMs > 0 {
+			out[repo] = time.Duration(bs.DurationMs) * time.Millisecond
+		}
+	}
+	return out
+}
+
+// retried reports whether a repo whose last build has status is built again
+// even if it did not change since.
+func retried(status string) bool {
+	return status == "failed" || status == "blocked" || status == "interrupted" || status == "verified"
+}
+
 func (m *BuildManifest) ensureState(repo string) *BuildState {
 	bs, ok := m.Repos[repo]
 	if !ok {
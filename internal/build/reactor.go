@@ -0,0 +1,230 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+	"github.com/fireflyframework/fireflyframework-cli/internal/git"
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+)
+
+// ReactorDir returns the directory of the generated aggregator POM,
+// <repos_path>/.flywork/reactor. It is removed after the build.
+func ReactorDir(reposDir string) string {
+	return filepath.Join(reposDir, config.FireflyDir, "reactor")
+}
+
+// reactorRepo is a repo taking part in a reactor build.
+type reactorRepo struct {
+	repo        string
+	layer       int
+	index       int
+	sha         string
+	fingerprint string
+}
+
+// reactorOutcome is a repo's result, read from the reactor summary.
+type reactorOutcome struct {
+	status   string // maven.ModuleSuccess, ModuleFailure or ModuleSkipped; "" if not in the summary
+	module   string // first failed module
+	duration time.Duration
+}
+
+// reactor builds the plan with a single Maven reactor. Skipped and cached
+// repos are handled first; the rest are listed as <modules> of a generated
+// aggregator POM and built with one mvn -T <Jobs> clean install, letting
+// Maven order and parallelize the modules. The failure policy maps to
+// --fail-at-end (keep going), --fail-fast or --fail-never. The reactor
+// summary is then parsed to map each module's status back to its repo: a
+// repo failed if any of its modules failed, and is blocked if Maven skipped
+// its modules because a dependency failed. Per-repo maven_args and
+// skip_tests overlay settings do not apply to a reactor build.
+func (r *buildRun) reactor(sub *dag.Graph, layers [][]string) error {
+	var repos []reactorRepo
+	var moduleDirs []string
+	idx := 0
+	for layerIdx, layer := range layers {
+		for _, repo := range layer {
+			idx++
+			dir := filepath.Join(r.opts.ReposDir, repo)
+			if r.skip(repo) {
+				r.start(layerIdx, repo, idx)
+				r.finish(layerIdx, idx, BuildResult{Repo: repo, Skipped: true})
+				continue
+			}
+			sha, _ := git.HeadSHA(dir)
+			fingerprint, _ := Fingerprint(dir)
+			if r.restore(repo, sha, fingerprint) {
+				r.start(layerIdx, repo, idx)
+				r.finish(layerIdx, idx, BuildResult{Repo: repo, Cached: true})
+				continue
+			}
+			repos = append(repos, reactorRepo{repo, layerIdx, idx, sha, fingerprint})
+			moduleDirs = append(moduleDirs, dir)
+		}
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+
+	reactorDir := ReactorDir(r.opts.ReposDir)
+	pomPath, err := maven.WriteAggregatorPOM(reactorDir, moduleDirs)
+	if err != nil {
+		return fmt.Errorf("failed to write aggregator POM: %w", err)
+	}
+	defer os.RemoveAll(reactorDir)
+
+	for _, rr := range repos {
+		r.start(rr.layer, rr.repo, rr.index)
+	}
+
+	var policyArg string
+	switch r.opts.FailurePolicy {
+	case dag.FailFast:
+		policyArg = "--fail-fast"
+	case dag.ForceContinue:
+		policyArg = "--fail-never"
+	default:
+		policyArg = "--fail-at-end"
+	}
+	started := time.Now()
+	output, buildErr := maven.ReactorInstall(r.ctx, pomPath, r.runOptions("reactor"), r.opts.Jobs, r.opts.SkipTests, policyArg)
+	elapsed := time.Since(started)
+
+	if err := r.ctx.Err(); err != nil && buildErr != nil {
+		r.opts.Logs.Record("reactor", err)
+		// The modules Maven completed before the interruption are not
+		// recorded: the reactor summary is only printed at the end.
+		for _, rr := range repos {
+			r.manifest.MarkInterrupted(rr.repo, rr.sha)
+			r.finish(rr.layer, rr.index, BuildResult{Repo: rr.repo, Interrupted: true, Error: err})
+		}
+		return nil
+	}
+
+	outcomes := reactorOutcomes(r.opts.ReposDir, repos, maven.ParseReactorSummary(output))
+
+	// The reactor output is logged once for the whole run; failed repos
+	// point to it.
+	logFile := r.opts.Logs.Record("reactor", buildErr)
+
+	// Repos whose modules Maven skipped are blocked by their nearest failed
+	// dependency, or by the first failure when Maven stopped early.
+	failed := make(map[string]bool)
+	firstFailure := ""
+	for _, rr := range repos {
+		if outcomes[rr.repo].status == maven.ModuleFailure {
+			failed[rr.repo] = true
+			if firstFailure == "" {
+				firstFailure = rr.repo
+			}
+		}
+	}
+	// The reactor log interleaves all modules, so its diagnosis is only
+	// attributed to a repo when exactly one failed.
+	var diagnosis *maven.Diagnosis
+	if len(failed) == 1 && len(output) > 0 {
+		diagnosis = diagnose(r.opts.ReposDir, firstFailure, output)
+	}
+
+	for _, rr := range repos {
+		o := outcomes[rr.repo]
+		res := BuildResult{Repo: rr.repo}
+		switch {
+		case o.status == maven.ModuleFailure:
+			res.Error = fmt.Errorf("module %s failed in the reactor build", o.module)
+			res.LogFile, res.Diagnosis = logFile, diagnosis
+			r.record(rr.repo, rr.sha, rr.fingerprint, res.Error, o.duration)
+		case o.status == maven.ModuleSkipped:
+			blockedBy := firstFailure
+			for _, dep := range sub.TransitiveDependenciesOf(rr.repo) {
+				if failed[dep] {
+					blockedBy = dep
+					break
+				}
+			}
+			if blockedBy == "" {
+				res.Error = fmt.Errorf("skipped by the reactor build")
+				res.LogFile = logFile
+				r.manifest.MarkFailed(rr.repo, rr.sha, res.Error)
+				break
+			}
+			res.Blocked, res.BlockedBy = true, blockedBy
+			r.manifest.MarkBlocked(rr.repo, blockedBy)
+		case o.status == "" && buildErr != nil:
+			// Not in the summary: Maven failed before building any module,
+			// e.g. on an invalid POM.
+			res.Error = fmt.Errorf("reactor build failed: %w", buildErr)
+			res.LogFile = logFile
+			r.record(rr.repo, rr.sha, rr.fingerprint, res.Error, elapsed)
+		default:
+			d := o.duration
+			if o.status == "" {
+				d = elapsed
+			}
+			r.record(rr.repo, rr.sha, rr.fingerprint, nil, d)
+		}
+		r.finish(rr.layer, rr.index, res)
+	}
+	return nil
+}
+
+// reactorOutcomes maps the modules of a reactor summary back to the repos
+// that declare them. A repo's status is the worst status of its modules and
+// its duration their sum.
+func reactorOutcomes(reposDir string, repos []reactorRepo, summary []maven.ModuleResult) map[string]reactorOutcome {
+	repoOf := make(map[string]string)
+	for _, rr := range repos {
+		names, _ := maven.ModuleNames(filepath.Join(reposDir, rr.repo))
+		for _, name := range names {
+			if _, taken := repoOf[name]; !taken {
+				repoOf[name] = rr.repo
+			}
+		}
+	}
+
+	rank := map[string]int{"": 0, maven.ModuleSuccess: 1, maven.ModuleSkipped: 2, maven.ModuleFailure: 3}
+	outcomes := make(map[string]reactorOutcome)
+	for _, m := range summary {
+		repo, ok := repoOf[m.Name]
+		if !ok {
+			// The summary appends the version when it differs from the
+			// aggregator's; it may not be resolvable from the POM alone.
+			if i := strings.LastIndex(m.Name, " "); i > 0 {
+				repo, ok = repoOf[m.Name[:i]]
+			}
+		}
+		if !ok {
+			continue
+		}
+		o := outcomes[repo]
+		o.duration += m.Duration
+		if rank[m.Status] > rank[o.status] {
+			o.status = m.Status
+			if m.Status == maven.ModuleFailure {
+				o.module = m.Name
+			}
+		}
+		outcomes[repo] = o
+	}
+	return outcomes
+}
@@ -0,0 +1,336 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache is a content-addressed store of installed Maven artifacts.
+// An entry holds the files a repository build installed into ~/.m2, keyed by
+// the repository's source fingerprint, the keys of its dependencies and the
+// JDK, so that a build of the exact same inputs can be restored instead of
+// re-running Maven.
+package cache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+)
+
+// ErrNotFound is returned by a Backend when it has no entry for a key.
+var ErrNotFound = errors.New("cache entry not found")
+
+// Backend stores cache entries as opaque archives. Cancelling the context
+// of Get or Put aborts the transfer.
+type Backend interface {
+	// Name describes the backend for display.
+	Name() string
+	// Get opens the archive stored under key, or returns ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Put stores the archive read from r under key.
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// Entry describes a cached build.
+type Entry struct {
+	Key         string           `json:"key"`
+	Repo        string           `json:"repo"`
+	Fingerprint string           `json:"fingerprint"`
+	JDK         string           `json:"jdk,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	Artifacts   []maven.Artifact `json:"artifacts"`
+}
+
+// metaFile is the name of the Entry inside an archive; artifact files are
+// stored under repository/ relative to the local Maven repository.
+const (
+	metaFile   = "flywork-cache.json"
+	repoPrefix = "repository/"
+)
+
+// Dir returns the default local cache directory (~/.flywork/cache).
+func Dir() string {
+	return filepath.Join(config.FlyworkHome(), "cache")
+}
+
+// Cache looks entries up in its backends in order and stores new entries in
+// all of them. A hit in a later backend (e.g. a shared HTTP cache) is copied
+// into the earlier ones. Its methods are safe for concurrent use.
+type Cache struct {
+	backends []Backend
+
+	mu    sync.Mutex
+	stats *Stats
+}
+
+// New returns a cache over the given backends, the fastest first.
+func New(backends ...Backend) *Cache {
+	return &Cache{backends: backends}
+}
+
+// Open returns the cache configured in cfg: the local directory backend,
+// followed by an HTTP backend when cache_url is set. It returns nil when the
+// build cache is disabled.
+func Open(cfg *config.Config) *Cache {
+	if !cfg.BuildCache {
+		return nil
+	}
+	backends := []Backend{NewDirBackend(Dir())}
+	if cfg.CacheURL != "" {
+		backends = append(backends, NewHTTPBackend(cfg.CacheURL, os.Getenv("FLYWORK_CACHE_TOKEN")))
+	}
+	return New(backends...)
+}
+
+// Backends returns the cache's backends in lookup order.
+func (c *Cache) Backends() []Backend {
+	return c.backends
+}
+
+// Store archives the files of every artifact in the local Maven repository
+// and saves them under e.Key. Cancelling ctx aborts the upload.
+func (c *Cache) Store(ctx context.Context, e Entry) error {
+	tmp, err := os.CreateTemp("", "flywork-cache-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := writeArchive(tmp, e); err != nil {
+		return fmt.Errorf("archive %s: %w", e.Repo, err)
+	}
+	for _, b := range c.backends {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := b.Put(ctx, e.Key, tmp); err != nil {
+			return fmt.Errorf("%s: %w", b.Name(), err)
+		}
+	}
+	c.record(func(s *Stats) { s.Stores++ })
+	return nil
+}
+
+// Restore extracts the entry stored under key into the local Maven
+// repository. It returns ErrNotFound on a cache miss. Cancelling ctx aborts
+// the download.
+func (c *Cache) Restore(ctx context.Context, key string) (*Entry, error) {
+	for i, b := range c.backends {
+		rc, err := b.Get(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			c.record(func(s *Stats) { s.Misses++ })
+			return nil, fmt.Errorf("%s: %w", b.Name(), err)
+		}
+		e, err := c.restoreFrom(ctx, rc, key, c.backends[:i])
+		rc.Close()
+		if err != nil {
+			c.record(func(s *Stats) { s.Misses++ })
+			return nil, err
+		}
+		c.record(func(s *Stats) { s.Hits++ })
+		return e, nil
+	}
+	c.record(func(s *Stats) { s.Misses++ })
+	return nil, ErrNotFound
+}
+
+// restoreFrom extracts an archive and, when it came from a later backend,
+// copies it into the earlier ones.
+func (c *Cache) restoreFrom(ctx context.Context, rc io.Reader, key string, earlier []Backend) (*Entry, error) {
+	if len(earlier) == 0 {
+		return extractArchive(rc, maven.LocalRepository())
+	}
+	tmp, err := os.CreateTemp("", "flywork-cache-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if _, err := io.Copy(tmp, rc); err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	e, err := extractArchive(tmp, maven.LocalRepository())
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range earlier {
+		if _, err := tmp.Seek(0, io.SeekStart); err == nil {
+			_ = b.Put(ctx, key, tmp)
+		}
+	}
+	return e, nil
+}
+
+// artifactFiles lists the files of an artifact's version directory in the
+// local repository, relative to it. Resolution markers are left out.
+func artifactFiles(a maven.Artifact) ([]string, error) {
+	rel := filepath.Join(strings.ReplaceAll(a.GroupID, ".", string(filepath.Separator)), a.ArtifactID, a.Version)
+	entries, err := os.ReadDir(filepath.Join(maven.LocalRepository(), rel))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, de := range entries {
+		name := de.Name()
+		if de.IsDir() || strings.HasSuffix(name, ".lastUpdated") || name == "_remote.repositories" {
+			continue
+		}
+		files = append(files, filepath.Join(rel, name))
+	}
+	return files, nil
+}
+
+func writeArchive(w io.Writer, e Entry) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	meta, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: metaFile, Mode: 0644, Size: int64(len(meta)), ModTime: e.CreatedAt}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(meta); err != nil {
+		return err
+	}
+
+	local := maven.LocalRepository()
+	for _, a := range e.Artifacts {
+		files, err := artifactFiles(a)
+		if err != nil {
+			return err
+		}
+		for _, rel := range files {
+			if err := addFile(tw, filepath.Join(local, rel), repoPrefix+filepath.ToSlash(rel)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addFile(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// extractArchive writes the artifact files of an archive below localRepo and
+// returns its Entry. Paths escaping localRepo are rejected.
+func extractArchive(r io.Reader, localRepo string) (*Entry, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var e *Entry
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Name == metaFile {
+			var meta Entry
+			if err := json.NewDecoder(tr).Decode(&meta); err != nil {
+				return nil, fmt.Errorf("read %s: %w", metaFile, err)
+			}
+			e = &meta
+			continue
+		}
+		rel, ok := strings.CutPrefix(hdr.Name, repoPrefix)
+		if !ok || hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		dest := filepath.Join(localRepo, filepath.FromSlash(rel))
+		if !strings.HasPrefix(dest, filepath.Clean(localRepo)+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid path in cache entry: %s", hdr.Name)
+		}
+		if err := writeFile(dest, tr); err != nil {
+			return nil, err
+		}
+	}
+	if e == nil {
+		return nil, fmt.Errorf("cache entry without %s", metaFile)
+	}
+	return e, nil
+}
+
+// writeFile writes r to path atomically, so a concurrent Maven build never
+// sees a half-written jar.
+func writeFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".flywork-*")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
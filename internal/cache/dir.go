@@ -0,0 +1,149 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const entryExt = ".tar.gz"
+
+// DirBackend stores entries as <dir>/<key[:2]>/<key>.tar.gz.
+type DirBackend struct {
+	dir string
+}
+
+// NewDirBackend returns a backend storing entries under dir.
+func NewDirBackend(dir string) *DirBackend {
+	return &DirBackend{dir: dir}
+}
+
+// Name implements Backend.
+func (d *DirBackend) Name() string {
+	return d.dir
+}
+
+func (d *DirBackend) path(key string) string {
+	shard := key
+	if len(shard) > 2 {
+		shard = shard[:2]
+	}
+	return filepath.Join(d.dir, shard, key+entryExt)
+}
+
+// Get implements Backend. A hit refreshes the entry's modification time,
+// which Prune uses as the last-used time.
+func (d *DirBackend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	path := d.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return f, nil
+}
+
+// Put implements Backend. The entry is written atomically.
+func (d *DirBackend) Put(ctx context.Context, key string, r io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeFile(d.path(key), r)
+}
+
+// StoredEntry is an entry in a DirBackend.
+type StoredEntry struct {
+	Key     string
+	Size    int64
+	LastUse time.Time
+	path    string
+}
+
+// List returns the stored entries, least recently used first.
+func (d *DirBackend) List() ([]StoredEntry, error) {
+	var out []StoredEntry
+	err := filepath.WalkDir(d.dir, func(path string, de os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if de.IsDir() || !strings.HasSuffix(de.Name(), entryExt) {
+			return nil
+		}
+		info, err := de.Info()
+		if err != nil {
+			return nil
+		}
+		out = append(out, StoredEntry{
+			Key:     strings.TrimSuffix(de.Name(), entryExt),
+			Size:    info.Size(),
+			LastUse: info.ModTime(),
+			path:    path,
+		})
+		return nil
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].LastUse.Before(out[j].LastUse) })
+	return out, err
+}
+
+// Prune removes entries unused for longer than maxAge (0 = no age limit) and
+// then the least recently used entries until the total size is at most
+// maxBytes (0 = no size limit). It returns the removed entries.
+func (d *DirBackend) Prune(maxAge time.Duration, maxBytes int64) ([]StoredEntry, error) {
+	entries, err := d.List()
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+
+	var removed []StoredEntry
+	cutoff := time.Now().Add(-maxAge)
+	for _, e := range entries {
+		expired := maxAge > 0 && e.LastUse.Before(cutoff)
+		oversized := maxBytes > 0 && total > maxBytes
+		if !expired && !oversized {
+			continue
+		}
+		if err := os.Remove(e.path); err != nil {
+			return removed, err
+		}
+		total -= e.Size
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// Clear removes every entry and the statistics.
+func (d *DirBackend) Clear() error {
+	return os.RemoveAll(d.dir)
+}
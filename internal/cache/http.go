@@ -0,0 +1,91 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPBackend stores entries on a shared HTTP server as <url>/<key>.tar.gz,
+// read with GET and written with PUT (e.g. a WebDAV share, nginx with
+// dav_methods, or an S3 bucket behind a signing proxy).
+type HTTPBackend struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPBackend returns a backend for the server at url. A non-empty token
+// is sent as a bearer token.
+func NewHTTPBackend(url, token string) *HTTPBackend {
+	return &HTTPBackend{
+		url:    strings.TrimRight(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Name implements Backend.
+func (h *HTTPBackend) Name() string {
+	return h.url
+}
+
+func (h *HTTPBackend) request(ctx context.Context, method, key string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, h.url+"/"+key+entryExt, body)
+	if err != nil {
+		return nil, err
+	}
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	return h.client.Do(req)
+}
+
+// Get implements Backend. Cancelling ctx also aborts reading the returned
+// body.
+func (h *HTTPBackend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := h.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", key, resp.Status)
+	}
+}
+
+// Put implements Backend.
+func (h *HTTPBackend) Put(ctx context.Context, key string, r io.Reader) error {
+	resp, err := h.request(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("PUT %s: %s", key, resp.Status)
+	}
+	return nil
+}
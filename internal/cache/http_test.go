@@ -0,0 +1,103 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPBackendCancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			_, _ = io.Copy(io.Discard, r.Body)
+		}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	h := NewHTTPBackend(srv.URL, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := h.Get(ctx, "key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := h.Put(ctx, "key", strings.NewReader("archive")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Put() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled requests took %v", elapsed)
+	}
+}
+
+func TestHTTPBackend(t *testing.T) {
+	stored := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			stored[r.URL.Path] = string(data)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			data, ok := stored[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, data)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	h := NewHTTPBackend(srv.URL+"/", "secret")
+
+	if _, err := h.Get(ctx, "abc"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() of a missing key = %v, want ErrNotFound", err)
+	}
+	if err := h.Put(ctx, "abc", strings.NewReader("archive")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored["/abc"+entryExt]; !ok {
+		t.Fatalf("stored %v", stored)
+	}
+	rc, err := h.Get(ctx, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if data, _ := io.ReadAll(rc); string(data) != "archive" {
+		t.Errorf("Get() = %q", data)
+	}
+
+	if err := NewHTTPBackend(srv.URL, "").Put(ctx, "abc", strings.NewReader("x")); err == nil {
+		t.Error("Put() without the token succeeded")
+	}
+}
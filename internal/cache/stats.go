@@ -0,0 +1,77 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const statsFile = "stats.json"
+
+// Stats counts cache lookups and stores across builds.
+type Stats struct {
+	Hits      int       `json:"hits"`
+	Misses    int       `json:"misses"`
+	Stores    int       `json:"stores"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LoadStats reads the statistics kept in the local cache directory. Missing
+// statistics are returned as zero.
+func LoadStats() (*Stats, error) {
+	data, err := os.ReadFile(filepath.Join(Dir(), statsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Stats{}, nil
+		}
+		return nil, err
+	}
+	var s Stats
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Stats) save() error {
+	s.UpdatedAt = time.Now()
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(Dir(), statsFile), data, 0644)
+}
+
+// record applies update to the persisted statistics. Failures are ignored:
+// statistics never fail a build.
+func (c *Cache) record(update func(*Stats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stats == nil {
+		s, err := LoadStats()
+		if err != nil {
+			s = &Stats{}
+		}
+		c.stats = s
+	}
+	update(c.stats)
+	_ = c.stats.save()
+}
@@ -0,0 +1,355 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package catalog is the single source of truth for the repositories the CLI
+// manages: their kind, clone URL, branch, publish target and version files.
+// Dependency edges between Maven repositories are kept in the dag package.
+package catalog
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+	"github.com/fireflyframework/fireflyframework-cli/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogFile is the name of the user catalog file, looked up in ~/.flywork
+// (global) and in <repos_path>/.flywork (per workspace).
+const CatalogFile = "repos.yaml"
+
+//go:embed repos.yaml
+var embeddedCatalog []byte
+
+// Kind is the build system of a repository.
+type Kind string
+
+const (
+	KindMaven  Kind = "maven"
+	KindPython Kind = "python"
+	KindDocs   Kind = "docs"
+)
+
+// PublishTarget is where publish delivers a repository's artifacts.
+type PublishTarget string
+
+const (
+	// PublishGitHubPackages deploys Maven artifacts to GitHub Packages.
+	PublishGitHubPackages PublishTarget = "github-packages"
+	// PublishGitHubRelease uploads Python wheels and sdists as GitHub
+	// Release assets.
+	PublishGitHubRelease PublishTarget = "github-release"
+	// PublishNone never publishes the repository.
+	PublishNone PublishTarget = "none"
+)
+
+// Repo is a catalog entry.
+type Repo struct {
+	Name         string        `yaml:"name" json:"name"`
+	Kind         Kind          `yaml:"kind,omitempty" json:"kind"`
+	URL          string        `yaml:"url,omitempty" json:"url,omitempty"`
+	Branch       string        `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Publish      PublishTarget `yaml:"publish,omitempty" json:"publish"`
+	VersionFiles []string      `yaml:"version_files,omitempty" json:"version_files,omitempty"`
+	Owners       []string      `yaml:"owners,omitempty" json:"owners,omitempty"`
+	Tags         []string      `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Description  string        `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// CloneURL returns the repo's git URL, defaulting to the GitHub repository
+// of the same name in org.
+func (r Repo) CloneURL(org string) string {
+	if r.URL != "" {
+		return r.URL
+	}
+	return git.RepoURL(org, r.Name)
+}
+
+// CloneBranch returns the repo's branch, or def if none is declared.
+func (r Repo) CloneBranch(def string) string {
+	if r.Branch != "" {
+		return r.Branch
+	}
+	return def
+}
+
+// IsMaven reports whether the repo is built with Maven.
+func (r Repo) IsMaven() bool {
+	return r.Kind == KindMaven
+}
+
+// HasTag reports whether the repo carries the given tag.
+func (r Repo) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Catalog is an ordered set of repositories.
+type Catalog struct {
+	Repos []Repo `yaml:"repos"`
+
+	// Sources lists the catalog and dag.yaml overlay files that were merged,
+	// in order; the embedded catalog is listed as "embedded".
+	Sources []string `yaml:"-"`
+
+	index map[string]int
+}
+
+// Embedded returns the catalog compiled into the binary.
+func Embedded() *Catalog {
+	c := embedded()
+	c.applyDefaults()
+	return c
+}
+
+func embedded() *Catalog {
+	c, err := parse(embeddedCatalog)
+	if err != nil {
+		panic(fmt.Sprintf("embedded repository catalog: %v", err))
+	}
+	c.Sources = []string{"embedded"}
+	return c
+}
+
+// Paths returns the user catalog files consulted for the workspace at
+// reposDir, in merge order.
+func Paths(reposDir string) []string {
+	paths := []string{filepath.Join(config.FlyworkHome(), CatalogFile)}
+	if reposDir != "" {
+		paths = append(paths, filepath.Join(reposDir, config.FireflyDir, CatalogFile))
+	}
+	return paths
+}
+
+// Load returns the embedded catalog merged with the global and per-workspace
+// repos.yaml files, then with the repositories declared in the dag.yaml
+// overlays, which are Maven repositories unless a catalog file says
+// otherwise. Later files override the fields they set; missing files are
+// ignored.
+func Load(reposDir string) (*Catalog, error) {
+	c := embedded()
+	for _, path := range Paths(reposDir) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		user, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		for _, r := range user.Repos {
+			c.merge(r)
+		}
+		c.Sources = append(c.Sources, path)
+	}
+
+	overlay, err := dag.LoadOverlay(reposDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range overlay.Repos {
+		c.merge(Repo{Name: r.Name, URL: r.URL, Branch: r.Branch})
+	}
+	c.Sources = append(c.Sources, overlay.Sources...)
+	c.applyDefaults()
+	return c, nil
+}
+
+func parse(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	raw := c.Repos
+	c.Repos = nil
+	c.index = make(map[string]int, len(raw))
+	for _, r := range raw {
+		if r.Name == "" {
+			return nil, fmt.Errorf("repository entry without a name")
+		}
+		if err := r.validate(); err != nil {
+			return nil, err
+		}
+		c.merge(r)
+	}
+	return &c, nil
+}
+
+func (r Repo) validate() error {
+	switch r.Kind {
+	case "", KindMaven, KindPython, KindDocs:
+	default:
+		return fmt.Errorf("%s: unknown kind %q (want maven, python or docs)", r.Name, r.Kind)
+	}
+	switch r.Publish {
+	case "", PublishGitHubPackages, PublishGitHubRelease, PublishNone:
+	default:
+		return fmt.Errorf("%s: unknown publish target %q (want github-packages, github-release or none)", r.Name, r.Publish)
+	}
+	return nil
+}
+
+func (c *Catalog) merge(r Repo) {
+	i, ok := c.index[r.Name]
+	if !ok {
+		c.index[r.Name] = len(c.Repos)
+		c.Repos = append(c.Repos, r)
+		return
+	}
+	cur := &c.Repos[i]
+	if r.Kind != "" {
+		cur.Kind = r.Kind
+	}
+	if r.URL != "" {
+		cur.URL = r.URL
+	}
+	if r.Branch != "" {
+		cur.Branch = r.Branch
+	}
+	if r.Publish != "" {
+		cur.Publish = r.Publish
+	}
+	if len(r.VersionFiles) > 0 {
+		cur.VersionFiles = r.VersionFiles
+	}
+	if len(r.Owners) > 0 {
+		cur.Owners = r.Owners
+	}
+	if len(r.Tags) > 0 {
+		cur.Tags = r.Tags
+	}
+	if r.Description != "" {
+		cur.Description = r.Description
+	}
+}
+
+// applyDefaults fills in the kind and the publish target of every entry.
+func (c *Catalog) applyDefaults() {
+	for i := range c.Repos {
+		r := &c.Repos[i]
+		if r.Kind == "" {
+			r.Kind = KindMaven
+		}
+		if r.Publish == "" {
+			switch r.Kind {
+			case KindMaven:
+				r.Publish = PublishGitHubPackages
+			case KindPython:
+				r.Publish = PublishGitHubRelease
+			default:
+				r.Publish = PublishNone
+			}
+		}
+	}
+}
+
+// Get returns the entry for a repository.
+func (c *Catalog) Get(name string) (Repo, bool) {
+	i, ok := c.index[name]
+	if !ok {
+		return Repo{}, false
+	}
+	return c.Repos[i], true
+}
+
+// Has reports whether the catalog lists a repository.
+func (c *Catalog) Has(name string) bool {
+	_, ok := c.index[name]
+	return ok
+}
+
+// Names returns every repository name in catalog order.
+func (c *Catalog) Names() []string {
+	out := make([]string, len(c.Repos))
+	for i, r := range c.Repos {
+		out[i] = r.Name
+	}
+	return out
+}
+
+// OfKind returns the entries of the given kind in catalog order.
+func (c *Catalog) OfKind(k Kind) []Repo {
+	var out []Repo
+	for _, r := range c.Repos {
+		if r.Kind == k {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Buildable reports whether a repository in the DAG should be built with
+// Maven. Repositories missing from the catalog are assumed to be Maven.
+func (c *Catalog) Buildable(name string) bool {
+	r, ok := c.Get(name)
+	return !ok || r.IsMaven()
+}
+
+// Publishable reports whether publish should deploy a repository's Maven
+// artifacts. Repositories missing from the catalog are assumed publishable.
+func (c *Catalog) Publishable(name string) bool {
+	r, ok := c.Get(name)
+	return !ok || (r.IsMaven() && r.Publish != PublishNone)
+}
+
+// Mismatch describes a disagreement between the catalog and the DAG.
+type Mismatch struct {
+	// NotInGraph lists Maven repositories that are not DAG nodes, so they
+	// would never be built.
+	NotInGraph []string `json:"not_in_graph"`
+	// NotInCatalog lists DAG nodes that the catalog does not list.
+	NotInCatalog []string `json:"not_in_catalog"`
+	// NotMaven lists DAG nodes that the catalog declares as another kind.
+	NotMaven []string `json:"not_maven"`
+}
+
+// Empty reports whether the catalog and the DAG agree.
+func (m Mismatch) Empty() bool {
+	return len(m.NotInGraph) == 0 && len(m.NotInCatalog) == 0 && len(m.NotMaven) == 0
+}
+
+// Check compares the catalog's Maven repositories with the nodes of g.
+func (c *Catalog) Check(g *dag.Graph) Mismatch {
+	m := Mismatch{NotInGraph: []string{}, NotInCatalog: []string{}, NotMaven: []string{}}
+	for _, r := range c.Repos {
+		if r.IsMaven() && !g.HasNode(r.Name) {
+			m.NotInGraph = append(m.NotInGraph, r.Name)
+		}
+	}
+	for _, id := range g.Nodes() {
+		r, ok := c.Get(id)
+		switch {
+		case !ok:
+			m.NotInCatalog = append(m.NotInCatalog, id)
+		case !r.IsMaven():
+			m.NotMaven = append(m.NotMaven, id)
+		}
+	}
+	sort.Strings(m.NotInGraph)
+	sort.Strings(m.NotInCatalog)
+	sort.Strings(m.NotMaven)
+	return m
+}
@@ -0,0 +1,70 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"testing"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+)
+
+func TestEmbeddedCatalogMatchesFrameworkGraph(t *testing.T) {
+	m := Embedded().Check(dag.FrameworkGraph())
+	if !m.Empty() {
+		t.Errorf("embedded catalog and framework graph disagree: %+v", m)
+	}
+}
+
+func TestEmbeddedCatalogHasOwners(t *testing.T) {
+	for _, r := range Embedded().Repos {
+		if len(r.Owners) == 0 {
+			t.Errorf("%s has no owners", r.Name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	g := dag.New()
+	g.AddNode("a")
+	g.AddNode("b")
+	g.AddNode("docs")
+
+	c, err := parse([]byte(`
+repos:
+  - name: a
+  - name: c
+  - name: docs
+    kind: docs
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.applyDefaults()
+
+	m := c.Check(g)
+	for _, tc := range []struct {
+		name string
+		got  []string
+		want string
+	}{
+		{"NotInGraph", m.NotInGraph, "c"},
+		{"NotInCatalog", m.NotInCatalog, "b"},
+		{"NotMaven", m.NotMaven, "docs"},
+	} {
+		if len(tc.got) != 1 || tc.got[0] != tc.want {
+			t.Errorf("%s = %v, want [%s]", tc.name, tc.got, tc.want)
+		}
+	}
+}
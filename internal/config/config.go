@@ -18,7 +18,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
 	"gopkg.in/yaml.v3"
 )
 
@@ -38,6 +41,14 @@ var ValidKeys = []string{
 	"parent_version",
 	"cli_auto_update",
 	"branch",
+	"build_cache",
+	"cache_url",
+	"log_retention_runs",
+	"log_retention_days",
+	"maven_goals",
+	"maven_profiles",
+	"maven_args",
+	"maven_offline",
 }
 
 type Config struct {
@@ -48,6 +59,16 @@ type Config struct {
 	ParentVersion string `yaml:"parent_version"`
 	CLIAutoUpdate bool   `yaml:"cli_auto_update"`
 	Branch        string `yaml:"branch"`
+	BuildCache    bool   `yaml:"build_cache"`
+	CacheURL      string `yaml:"cache_url"`
+	// Build logs of older runs are removed once there are more than
+	// LogRetentionRuns runs or they are older than LogRetentionDays; 0
+	// disables a limit.
+	LogRetentionRuns int `yaml:"log_retention_runs"`
+	LogRetentionDays int `yaml:"log_retention_days"`
+	// Maven holds the goals, profiles, properties, arguments and environment
+	// variables of every Maven invocation; dag.yaml adds to them per repo.
+	Maven maven.Settings `yaml:"maven,omitempty"`
 }
 
 // GetField returns the value of a config key.
@@ -70,6 +91,25 @@ func (c *Config) GetField(key string) (string, bool) {
 		return "false", true
 	case "branch":
 		return c.Branch, true
+	case "build_cache":
+		if c.BuildCache {
+			return "true", true
+		}
+		return "false", true
+	case "cache_url":
+		return c.CacheURL, true
+	case "log_retention_runs":
+		return strconv.Itoa(c.LogRetentionRuns), true
+	case "log_retention_days":
+		return strconv.Itoa(c.LogRetentionDays), true
+	case "maven_goals":
+		return strings.Join(c.Maven.Goals, " "), true
+	case "maven_profiles":
+		return strings.Join(c.Maven.Profiles, ","), true
+	case "maven_args":
+		return strings.Join(c.Maven.Args, " "), true
+	case "maven_offline":
+		return strconv.FormatBool(c.Maven.Offline), true
 	default:
 		return "", false
 	}
@@ -92,6 +132,22 @@ func (c *Config) SetField(key, value string) bool {
 		c.CLIAutoUpdate = value == "true" || value == "1" || value == "yes"
 	case "branch":
 		c.Branch = value
+	case "build_cache":
+		c.BuildCache = value == "true" || value == "1" || value == "yes"
+	case "cache_url":
+		c.CacheURL = value
+	case "log_retention_runs":
+		c.LogRetentionRuns = nonNegative(value)
+	case "log_retention_days":
+		c.LogRetentionDays = nonNegative(value)
+	case "maven_goals":
+		c.Maven.Goals = strings.Fields(value)
+	case "maven_profiles":
+		c.Maven.Profiles = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	case "maven_args":
+		c.Maven.Args = strings.Fields(value)
+	case "maven_offline":
+		c.Maven.Offline = value == "true" || value == "1" || value == "yes"
 	default:
 		return false
 	}
@@ -108,7 +164,25 @@ func (c *Config) Fields() []KeyValue {
 		{"parent_version", c.ParentVersion},
 		{"cli_auto_update", fmt.Sprintf("%v", c.CLIAutoUpdate)},
 		{"branch", c.Branch},
+		{"build_cache", fmt.Sprintf("%v", c.BuildCache)},
+		{"cache_url", c.CacheURL},
+		{"log_retention_runs", strconv.Itoa(c.LogRetentionRuns)},
+		{"log_retention_days", strconv.Itoa(c.LogRetentionDays)},
+		{"maven_goals", strings.Join(c.Maven.Goals, " ")},
+		{"maven_profiles", strings.Join(c.Maven.Profiles, ",")},
+		{"maven_args", strings.Join(c.Maven.Args, " ")},
+		{"maven_offline", strconv.FormatBool(c.Maven.Offline)},
+	}
+}
+
+// nonNegative parses a count setting; anything but a non-negative integer
+// is 0, which disables the limit.
+func nonNegative(value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0
 	}
+	return n
 }
 
 // KeyValue is a simple key-value pair.
@@ -125,6 +199,10 @@ func DefaultConfig() *Config {
 		JavaVersion:   "25",
 		ParentVersion: "26.02.03",
 		Branch:        "develop",
+		BuildCache:    true,
+
+		LogRetentionRuns: 20,
+		LogRetentionDays: 30,
 	}
 }
 
@@ -169,5 +247,32 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	return os.WriteFile(filepath.Join(dir, ConfigFile), data, 0644)
+	return WriteFileAtomic(filepath.Join(dir, ConfigFile), data, 0644)
+}
+
+// WriteFileAtomic writes data to path through a temporary file in the same
+// directory that is renamed over path, so that an interrupted write never
+// leaves a truncated file behind.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
@@ -0,0 +1,167 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import "sort"
+
+// FailurePolicy decides what Schedule does with the rest of the plan once a
+// task has failed.
+type FailurePolicy int
+
+const (
+	// KeepGoing skips the transitive dependents of a failed node, which would
+	// otherwise build against stale artifacts, and runs everything else.
+	KeepGoing FailurePolicy = iota
+	// FailFast starts no new node after the first failure; tasks already
+	// running are allowed to finish.
+	FailFast
+	// ForceContinue runs every node regardless of failures.
+	ForceContinue
+)
+
+// String returns the policy's flag name.
+func (p FailurePolicy) String() string {
+	switch p {
+	case FailFast:
+		return "fail-fast"
+	case ForceContinue:
+		return "force-continue"
+	default:
+		return "keep-going"
+	}
+}
+
+// Task processes one repository of a build plan and reports whether it
+// succeeded. layer is the repo's logical layer in the graph being scheduled
+// (for display) and index its 1-based position in the layered plan, so
+// results can be stored in plan order regardless of completion order.
+type Task func(layer int, repo string, index int) bool
+
+// BlockedFunc is called for a node that Schedule does not run because of the
+// failure policy. blockedBy is the failed node that caused it to be skipped.
+type BlockedFunc func(layer int, repo string, index int, blockedBy string)
+
+// Schedule runs task for every node of g with at most jobs tasks in flight.
+// A node starts as soon as all of its dependencies in g have finished, so a
+// slow repo only holds up the repos that depend on it, not the whole next
+// layer. When several nodes are ready, the one earliest in the layered plan
+// starts first, so with jobs < 2 the nodes run one at a time in plan order.
+// After a task fails, policy decides which of the remaining nodes are passed
+// to onBlocked instead of being run.
+//
+// Tasks run on separate goroutines, so anything they share must be
+// synchronized by the caller; onBlocked is called from the caller's
+// goroutine. Schedule returns an error, without running anything, if g
+// contains a cycle.
+func Schedule(g *Graph, jobs int, policy FailurePolicy, task Task, onBlocked BlockedFunc) error {
+	layers, err := g.Layers()
+	if err != nil {
+		return err
+	}
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	layerOf := make(map[string]int, g.NodeCount())
+	indexOf := make(map[string]int, g.NodeCount())
+	index := 0
+	for l, layer := range layers {
+		for _, repo := range layer {
+			index++
+			layerOf[repo] = l
+			indexOf[repo] = index
+		}
+	}
+
+	// In-degree counting: a node becomes ready when its last dependency in
+	// g has finished, whether it ran or was blocked.
+	pending := make(map[string]int, g.NodeCount())
+	var ready []string
+	for _, repo := range g.ordered {
+		pending[repo] = len(g.edges[repo])
+		if pending[repo] == 0 {
+			ready = append(ready, repo)
+		}
+	}
+
+	finished := 0
+	complete := func(repo string) {
+		finished++
+		for dependent := range g.reverse[repo] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	blockedBy := make(map[string]string) // node → failed node that blocks it
+	firstFailure := ""
+
+	type outcome struct {
+		repo string
+		ok   bool
+	}
+	done := make(chan outcome)
+	running := 0
+	for finished < g.NodeCount() {
+		for len(ready) > 0 {
+			sort.Slice(ready, func(i, j int) bool { return indexOf[ready[i]] < indexOf[ready[j]] })
+			repo := ready[0]
+
+			cause, blocked := blockedBy[repo]
+			if policy == FailFast && firstFailure != "" {
+				cause, blocked = firstFailure, true
+			}
+			if blocked {
+				ready = ready[1:]
+				if onBlocked != nil {
+					onBlocked(layerOf[repo], repo, indexOf[repo], cause)
+				}
+				complete(repo)
+				continue
+			}
+
+			if running >= jobs {
+				break
+			}
+			ready = ready[1:]
+			running++
+			go func() {
+				done <- outcome{repo, task(layerOf[repo], repo, indexOf[repo])}
+			}()
+		}
+		if running == 0 {
+			break // everything left was blocked
+		}
+
+		o := <-done
+		running--
+		if !o.ok {
+			if firstFailure == "" {
+				firstFailure = o.repo
+			}
+			if policy == KeepGoing {
+				for _, dependent := range g.TransitiveDependentsOf(o.repo) {
+					if _, ok := blockedBy[dependent]; !ok {
+						blockedBy[dependent] = o.repo
+					}
+				}
+			}
+		}
+		complete(o.repo)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scheduleGraph returns two chains, c → b → a and e → d.
+func scheduleGraph() *Graph {
+	g := New()
+	for _, n := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(n)
+	}
+	g.AddEdge("b", "a")
+	g.AddEdge("c", "b")
+	g.AddEdge("e", "d")
+	return g
+}
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  FailurePolicy
+		fail    string
+		ran     []string
+		blocked map[string]string
+	}{
+		{
+			name:    "all succeed",
+			policy:  KeepGoing,
+			ran:     []string{"a", "b", "c", "d", "e"},
+			blocked: map[string]string{},
+		},
+		{
+			name:    "keep going blocks only transitive dependents",
+			policy:  KeepGoing,
+			fail:    "a",
+			ran:     []string{"a", "d", "e"},
+			blocked: map[string]string{"b": "a", "c": "a"},
+		},
+		{
+			name:    "keep going after a mid-chain failure",
+			policy:  KeepGoing,
+			fail:    "b",
+			ran:     []string{"a", "b", "d", "e"},
+			blocked: map[string]string{"c": "b"},
+		},
+		{
+			name:    "fail fast cancels queued work",
+			policy:  FailFast,
+			fail:    "a",
+			ran:     []string{"a"},
+			blocked: map[string]string{"b": "a", "c": "a", "d": "a", "e": "a"},
+		},
+		{
+			name:    "force continue runs dependents anyway",
+			policy:  ForceContinue,
+			fail:    "a",
+			ran:     []string{"a", "b", "c", "d", "e"},
+			blocked: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			blocked := map[string]string{}
+			err := Schedule(scheduleGraph(), 1, tt.policy, func(_ int, repo string, _ int) bool {
+				ran = append(ran, repo)
+				return repo != tt.fail
+			}, func(_ int, repo string, _ int, blockedBy string) {
+				blocked[repo] = blockedBy
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(ran)
+			if !reflect.DeepEqual(ran, tt.ran) {
+				t.Errorf("ran %v, want %v", ran, tt.ran)
+			}
+			if !reflect.DeepEqual(blocked, tt.blocked) {
+				t.Errorf("blocked %v, want %v", blocked, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestScheduleSequentialRunsInPlanOrder(t *testing.T) {
+	g := scheduleGraph()
+	layers, err := g.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []int
+	var plan []string
+	for _, layer := range layers {
+		plan = append(plan, layer...)
+	}
+	for i := range plan {
+		want = append(want, i+1)
+	}
+
+	var order []string
+	var indexes []int
+	err = Schedule(g, 1, KeepGoing, func(_ int, repo string, index int) bool {
+		order = append(order, repo)
+		indexes = append(indexes, index)
+		return true
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(order, plan) {
+		t.Errorf("order %v, want %v", order, plan)
+	}
+	if !reflect.DeepEqual(indexes, want) {
+		t.Errorf("indexes %v, want %v", indexes, want)
+	}
+}
+
+func TestScheduleParallelRespectsDependenciesAndJobs(t *testing.T) {
+	g := scheduleGraph()
+	var (
+		mu        sync.Mutex
+		done      = map[string]bool{}
+		inFlight  int
+		maxFlight int
+	)
+	err := Schedule(g, 2, KeepGoing, func(_ int, repo string, _ int) bool {
+		mu.Lock()
+		for _, dep := range g.DependenciesOf(repo) {
+			if !done[dep] {
+				t.Errorf("%s started before its dependency %s finished", repo, dep)
+			}
+		}
+		inFlight++
+		maxFlight = max(maxFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		done[repo] = true
+		mu.Unlock()
+		return true
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != g.NodeCount() {
+		t.Errorf("ran %d nodes, want %d", len(done), g.NodeCount())
+	}
+	if maxFlight > 2 {
+		t.Errorf("%d tasks in flight, want at most 2", maxFlight)
+	}
+}
+
+func TestScheduleFailFastLetsRunningTasksFinish(t *testing.T) {
+	// a fails while d is still running; d finishes, e and the a chain are
+	// blocked.
+	g := scheduleGraph()
+	var mu sync.Mutex
+	var ran []string
+	blocked := map[string]string{}
+	err := Schedule(g, 2, FailFast, func(_ int, repo string, _ int) bool {
+		if repo == "d" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		mu.Lock()
+		ran = append(ran, repo)
+		mu.Unlock()
+		return repo != "a"
+	}, func(_ int, repo string, _ int, blockedBy string) {
+		blocked[repo] = blockedBy
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(ran)
+	if want := []string{"a", "d"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if want := map[string]string{"b": "a", "c": "a", "e": "a"}; !reflect.DeepEqual(blocked, want) {
+		t.Errorf("blocked %v, want %v", blocked, want)
+	}
+}
+
+func TestScheduleCycle(t *testing.T) {
+	g := New()
+	g.AddNode("a")
+	g.AddNode("b")
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "a")
+
+	ran := false
+	err := Schedule(g, 1, KeepGoing, func(int, string, int) bool {
+		ran = true
+		return true
+	}, nil)
+	if err == nil {
+		t.Error("Schedule succeeded on a cyclic graph")
+	}
+	if ran {
+		t.Error("Schedule ran a task of a cyclic graph")
+	}
+}
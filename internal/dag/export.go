@@ -0,0 +1,377 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Export formats supported by Graph.Export.
+const (
+	FormatJSON     = "json"
+	FormatDOT      = "dot"
+	FormatMermaid  = "mermaid"
+	FormatGraphML  = "graphml"
+	FormatPlantUML = "plantuml"
+)
+
+// Formats lists every supported export format.
+var Formats = []string{FormatJSON, FormatDOT, FormatMermaid, FormatGraphML, FormatPlantUML}
+
+// ExportOptions configures Graph.Export.
+type ExportOptions struct {
+	Format string
+	// Status optionally maps repos to their last build status (success,
+	// failed, pending, ...). When set, nodes are colored accordingly.
+	Status map[string]string
+}
+
+// statuses lists the build statuses written to the build manifest, in the
+// order their diagram classes are declared.
+var statuses = []string{"success", "verified", "failed", "blocked", "interrupted", "pending"}
+
+// statusColors maps build statuses to fill colors used by the diagram formats.
+var statusColors = map[string]string{
+	"success":     "#28A745",
+	"verified":    "#17A2B8",
+	"failed":      "#DC3545",
+	"blocked":     "#FD7E14",
+	"interrupted": "#6F42C1",
+	"pending":     "#FFC107",
+}
+
+const unknownStatusColor = "#ADB5BD"
+
+func statusColor(status string) string {
+	if c, ok := statusColors[status]; ok {
+		return c
+	}
+	return unknownStatusColor
+}
+
+// Export renders the graph in the requested format. Every format clusters
+// nodes by layer; edges point from a repo to the repo it depends on.
+func (g *Graph) Export(opts ExportOptions) ([]byte, error) {
+	layers, err := g.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Format {
+	case FormatJSON, "":
+		return g.exportJSON(layers, opts.Status)
+	case FormatDOT:
+		return g.exportDOT(layers, opts.Status), nil
+	case FormatMermaid:
+		return g.exportMermaid(layers, opts.Status), nil
+	case FormatGraphML:
+		return g.exportGraphML(layers, opts.Status)
+	case FormatPlantUML:
+		return g.exportPlantUML(layers, opts.Status), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q (valid: %s)", opts.Format, strings.Join(Formats, ", "))
+	}
+}
+
+// Restrict returns the subgraph selected by the export filters:
+//   - from: the repo and everything it transitively depends on
+//   - to: the repo and everything that transitively depends on it
+//   - from and to together: only the repos on dependency paths from → to
+//   - affectedBy: the given repos and all of their transitive dependents
+//
+// Filters are intersected. Empty filters select the whole graph.
+func (g *Graph) Restrict(from, to string, affectedBy []string) (*Graph, error) {
+	for _, id := range append([]string{from, to}, affectedBy...) {
+		if id != "" && !g.nodes[id] {
+			return nil, fmt.Errorf("unknown repository: %s", id)
+		}
+	}
+
+	selected := make(map[string]bool, len(g.nodes))
+	for id := range g.nodes {
+		selected[id] = true
+	}
+	intersect := func(set map[string]bool) {
+		for id := range selected {
+			if !set[id] {
+				delete(selected, id)
+			}
+		}
+	}
+
+	if from != "" {
+		intersect(g.walk(from, g.edges))
+	}
+	if to != "" {
+		intersect(g.walk(to, g.reverse))
+	}
+	if len(affectedBy) > 0 {
+		affected := make(map[string]bool)
+		for _, id := range affectedBy {
+			for n := range g.walk(id, g.reverse) {
+				affected[n] = true
+			}
+		}
+		intersect(affected)
+	}
+
+	return g.Subgraph(selected), nil
+}
+
+// walk returns start plus every node reachable from it through adj.
+func (g *Graph) walk(start string, adj map[string]map[string]bool) map[string]bool {
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for next := range adj[node] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return visited
+}
+
+// sortedEdges returns all edges in node insertion order with sorted targets.
+func (g *Graph) sortedEdges() []Edge {
+	var out []Edge
+	for _, id := range g.ordered {
+		deps := g.DependenciesOf(id)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			out = append(out, Edge{From: id, To: dep})
+		}
+	}
+	return out
+}
+
+// nodeID turns a repo name into an identifier safe for every diagram syntax.
+func nodeID(repo string) string {
+	var b strings.Builder
+	for _, r := range repo {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func shortLabel(repo string) string {
+	return strings.TrimPrefix(repo, "fireflyframework-")
+}
+
+func (g *Graph) exportJSON(layers [][]string, status map[string]string) ([]byte, error) {
+	edges := make(map[string][]string, len(g.nodes))
+	for _, id := range g.ordered {
+		deps := g.DependenciesOf(id)
+		sort.Strings(deps)
+		if len(deps) > 0 {
+			edges[id] = deps
+		}
+	}
+
+	var st map[string]string
+	if status != nil {
+		st = make(map[string]string, len(g.nodes))
+		for _, id := range g.ordered {
+			if s, ok := status[id]; ok {
+				st[id] = s
+			}
+		}
+	}
+
+	return json.MarshalIndent(dagJSON{Layers: layers, Edges: edges, Status: st}, "", "  ")
+}
+
+func (g *Graph) exportDOT(layers [][]string, status map[string]string) []byte {
+	var b bytes.Buffer
+	b.WriteString("digraph fireflyframework {\n")
+	b.WriteString("  rankdir=BT;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#FFFFFF\", fontname=\"Helvetica\"];\n\n")
+
+	for i, layer := range layers {
+		fmt.Fprintf(&b, "  subgraph cluster_layer_%d {\n", i)
+		fmt.Fprintf(&b, "    label=\"Layer %d\";\n", i)
+		b.WriteString("    style=dashed;\n    color=\"#6C757D\";\n")
+		for _, repo := range layer {
+			attrs := fmt.Sprintf("label=\"%s\"", shortLabel(repo))
+			if status != nil {
+				attrs += fmt.Sprintf(", fillcolor=\"%s\"", statusColor(status[repo]))
+			}
+			fmt.Fprintf(&b, "    \"%s\" [%s];\n", repo, attrs)
+		}
+		b.WriteString("  }\n\n")
+	}
+
+	for _, e := range g.sortedEdges() {
+		fmt.Fprintf(&b, "  \"%s\" -> \"%s\";\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func (g *Graph) exportMermaid(layers [][]string, status map[string]string) []byte {
+	var b bytes.Buffer
+	b.WriteString("flowchart BT\n")
+
+	for i, layer := range layers {
+		fmt.Fprintf(&b, "  subgraph layer%d[\"Layer %d\"]\n", i, i)
+		for _, repo := range layer {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", nodeID(repo), shortLabel(repo))
+		}
+		b.WriteString("  end\n")
+	}
+
+	for _, e := range g.sortedEdges() {
+		fmt.Fprintf(&b, "  %s --> %s\n", nodeID(e.From), nodeID(e.To))
+	}
+
+	if status != nil {
+		for _, s := range statuses {
+			fmt.Fprintf(&b, "  classDef %s fill:%s,color:#FFFFFF\n", s, statusColor(s))
+		}
+		fmt.Fprintf(&b, "  classDef unknown fill:%s\n", unknownStatusColor)
+		for _, id := range g.ordered {
+			class := status[id]
+			if _, ok := statusColors[class]; !ok {
+				class = "unknown"
+			}
+			fmt.Fprintf(&b, "  class %s %s\n", nodeID(id), class)
+		}
+	}
+	return b.Bytes()
+}
+
+func (g *Graph) exportPlantUML(layers [][]string, status map[string]string) []byte {
+	var b bytes.Buffer
+	b.WriteString("@startuml\n")
+	b.WriteString("skinparam componentStyle rectangle\n\n")
+
+	for i, layer := range layers {
+		fmt.Fprintf(&b, "package \"Layer %d\" {\n", i)
+		for _, repo := range layer {
+			color := ""
+			if status != nil {
+				color = " " + statusColor(status[repo])
+			}
+			fmt.Fprintf(&b, "  [%s] as %s%s\n", shortLabel(repo), nodeID(repo), color)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for _, e := range g.sortedEdges() {
+		fmt.Fprintf(&b, "%s --> %s\n", nodeID(e.From), nodeID(e.To))
+	}
+	b.WriteString("@enduml\n")
+	return b.Bytes()
+}
+
+// GraphML document model.
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID    string        `xml:"id,attr"`
+	Data  []graphMLData `xml:"data"`
+	Graph *graphMLGraph `xml:"graph,omitempty"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (g *Graph) exportGraphML(layers [][]string, status map[string]string) ([]byte, error) {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "layer", For: "node", AttrName: "layer", AttrType: "int"},
+		},
+		Graph: graphMLGraph{ID: "fireflyframework", EdgeDefault: "directed"},
+	}
+	if status != nil {
+		doc.Keys = append(doc.Keys,
+			graphMLKey{ID: "status", For: "node", AttrName: "status", AttrType: "string"},
+			graphMLKey{ID: "color", For: "node", AttrName: "color", AttrType: "string"},
+		)
+	}
+
+	// Each layer is a nested graph (GraphML's notion of a cluster).
+	for i, layer := range layers {
+		cluster := graphMLNode{
+			ID:    fmt.Sprintf("layer_%d", i),
+			Data:  []graphMLData{{Key: "label", Value: fmt.Sprintf("Layer %d", i)}},
+			Graph: &graphMLGraph{ID: fmt.Sprintf("layer_%d:", i), EdgeDefault: "directed"},
+		}
+		for _, repo := range layer {
+			n := graphMLNode{ID: repo, Data: []graphMLData{
+				{Key: "label", Value: shortLabel(repo)},
+				{Key: "layer", Value: fmt.Sprintf("%d", i)},
+			}}
+			if status != nil {
+				n.Data = append(n.Data,
+					graphMLData{Key: "status", Value: status[repo]},
+					graphMLData{Key: "color", Value: statusColor(status[repo])},
+				)
+			}
+			cluster.Graph.Nodes = append(cluster.Graph.Nodes, n)
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, cluster)
+	}
+
+	for _, e := range g.sortedEdges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: e.From, Target: e.To})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
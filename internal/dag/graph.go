@@ -15,7 +15,6 @@
 package dag
 
 import (
-	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -211,6 +210,35 @@ func (g *Graph) TransitiveDependentsOf(id string) []string {
 	return result
 }
 
+// TransitiveDependenciesOf performs a BFS on forward edges from the given node,
+// returning all repos it transitively depends on. The starting node itself is
+// not included in the result.
+func (g *Graph) TransitiveDependenciesOf(id string) []string {
+	if !g.nodes[id] {
+		return nil
+	}
+
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+	var result []string
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for dep := range g.edges[node] {
+			if !visited[dep] {
+				visited[dep] = true
+				result = append(result, dep)
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
 // Subgraph returns a new Graph containing only the specified nodes and the
 // edges between them. Nodes not present in the original graph are ignored.
 func (g *Graph) Subgraph(nodes map[string]bool) *Graph {
@@ -240,28 +268,15 @@ func (g *Graph) Subgraph(nodes map[string]bool) *Graph {
 
 // dagJSON is the serialization format for ExportJSON.
 type dagJSON struct {
-	Layers [][]string            `json:"layers"`
-	Edges  map[string][]string   `json:"edges"`
+	Layers [][]string          `json:"layers"`
+	Edges  map[string][]string `json:"edges"`
+	Status map[string]string   `json:"status,omitempty"`
 }
 
 // ExportJSON exports the graph as JSON with layers and edges.
 // The output is suitable for consumption by GitHub Actions workflows.
 func (g *Graph) ExportJSON() ([]byte, error) {
-	layers, err := g.Layers()
-	if err != nil {
-		return nil, err
-	}
-
-	edges := make(map[string][]string, len(g.nodes))
-	for _, id := range g.ordered {
-		deps := g.DependenciesOf(id)
-		sort.Strings(deps)
-		if len(deps) > 0 {
-			edges[id] = deps
-		}
-	}
-
-	return json.MarshalIndent(dagJSON{Layers: layers, Edges: edges}, "", "  ")
+	return g.Export(ExportOptions{Format: FormatJSON})
 }
 
 // detectCycle finds and returns one cycle in the graph using DFS.
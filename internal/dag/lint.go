@@ -0,0 +1,258 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import "sort"
+
+// RedundantEdge is a declared edge that is already implied by a longer path.
+// Via is that path, starting with From and ending with To.
+type RedundantEdge struct {
+	Edge
+	Via []string `json:"via"`
+}
+
+// DeepRepo is a repository that sits in a deeper layer than its pom.xml
+// dependencies require. Extra lists its own edges that are not declared in
+// any pom; when empty, the repo is pushed down by an upstream repo instead.
+type DeepRepo struct {
+	Repo     string   `json:"repo"`
+	Layer    int      `json:"layer"`
+	MinLayer int      `json:"min_layer"`
+	Extra    []string `json:"extra_edges,omitempty"`
+}
+
+// LintReport is the result of Lint.
+type LintReport struct {
+	Nodes        int             `json:"nodes"`
+	Edges        int             `json:"edges"`
+	ReducedEdges int             `json:"reduced_edges"`
+	Redundant    []RedundantEdge `json:"redundant_edges"`
+	Cycles       [][]string      `json:"cycles"`
+	Deep         []DeepRepo      `json:"deeper_than_necessary"`
+	DepthChecked bool            `json:"depth_checked"`
+}
+
+// Clean reports whether the lint found nothing to fix.
+func (r *LintReport) Clean() bool {
+	return len(r.Cycles) == 0 && len(r.Redundant) == 0 && len(r.Deep) == 0
+}
+
+// Lint checks g for cycles, redundant edges and, when a workspace is given,
+// repos placed deeper than their pom.xml dependencies require. Redundancy
+// and depth are only checked when the graph is acyclic.
+func Lint(g *Graph, ws *Workspace) *LintReport {
+	r := &LintReport{
+		Nodes:     g.NodeCount(),
+		Edges:     g.EdgeCount(),
+		Redundant: []RedundantEdge{},
+		Cycles:    g.Cycles(),
+		Deep:      []DeepRepo{},
+	}
+	if len(r.Cycles) > 0 {
+		return r
+	}
+
+	r.Redundant = g.RedundantEdges()
+	r.ReducedEdges = r.Edges - len(r.Redundant)
+
+	if ws != nil && len(ws.Repos) > 0 {
+		r.DepthChecked = true
+		r.Deep = g.deepRepos(ws)
+	}
+	return r
+}
+
+// EdgeCount returns the number of dependency edges.
+func (g *Graph) EdgeCount() int {
+	n := 0
+	for _, deps := range g.edges {
+		n += len(deps)
+	}
+	return n
+}
+
+// StronglyConnectedComponents returns every strongly connected component of
+// the graph using Tarjan's algorithm. Components are returned in reverse
+// topological order (dependencies first); members are sorted by name.
+func (g *Graph) StronglyConnectedComponents() [][]string {
+	index := make(map[string]int, len(g.nodes))
+	low := make(map[string]int, len(g.nodes))
+	onStack := make(map[string]bool, len(g.nodes))
+	var stack []string
+	var sccs [][]string
+	next := 0
+
+	var connect func(node string)
+	connect = func(node string) {
+		index[node] = next
+		low[node] = next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		deps := g.DependenciesOf(node)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, seen := index[dep]; !seen {
+				connect(dep)
+				low[node] = min(low[node], low[dep])
+			} else if onStack[dep] {
+				low[node] = min(low[node], index[dep])
+			}
+		}
+
+		if low[node] == index[node] {
+			var scc []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				scc = append(scc, top)
+				if top == node {
+					break
+				}
+			}
+			sort.Strings(scc)
+			sccs = append(sccs, scc)
+		}
+	}
+
+	for _, id := range g.ordered {
+		if _, seen := index[id]; !seen {
+			connect(id)
+		}
+	}
+	return sccs
+}
+
+// Cycles returns every strongly connected component that contains a cycle:
+// components with more than one repo, and repos that depend on themselves.
+func (g *Graph) Cycles() [][]string {
+	cycles := [][]string{}
+	for _, scc := range g.StronglyConnectedComponents() {
+		if len(scc) > 1 || g.edges[scc[0]][scc[0]] {
+			cycles = append(cycles, scc)
+		}
+	}
+	return cycles
+}
+
+// RedundantEdges returns the declared edges that are implied by a longer
+// dependency path, e.g. backoffice → eda when backoffice → core → eda.
+// Removing all of them yields the transitive reduction. The graph must be
+// acyclic.
+func (g *Graph) RedundantEdges() []RedundantEdge {
+	out := []RedundantEdge{}
+	for _, e := range g.sortedEdges() {
+		var via []string
+		deps := g.DependenciesOf(e.From)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if dep == e.To {
+				continue
+			}
+			if p := g.ShortestPath(dep, e.To); p != nil && (via == nil || len(p)+1 < len(via)) {
+				via = append([]string{e.From}, p...)
+			}
+		}
+		if via != nil {
+			out = append(out, RedundantEdge{Edge: e, Via: via})
+		}
+	}
+	return out
+}
+
+// TransitiveReduction returns a copy of the graph without redundant edges.
+// It has the same reachability, and therefore the same layers, as g.
+func (g *Graph) TransitiveReduction() *Graph {
+	redundant := make(map[Edge]bool)
+	for _, r := range g.RedundantEdges() {
+		redundant[r.Edge] = true
+	}
+
+	out := New()
+	for _, id := range g.ordered {
+		out.AddNode(id)
+	}
+	for _, e := range g.sortedEdges() {
+		if !redundant[e] {
+			out.AddEdge(e.From, e.To)
+		}
+	}
+	return out
+}
+
+// deepRepos compares each repo's layer with the layer it would have if the
+// edges not declared in any workspace pom.xml were removed.
+func (g *Graph) deepRepos(ws *Workspace) []DeepRepo {
+	extra := make(map[Edge]bool)
+	for _, e := range Verify(g, ws).Extra {
+		extra[e] = true
+	}
+	if len(extra) == 0 {
+		return []DeepRepo{}
+	}
+
+	lean := New()
+	for _, id := range g.ordered {
+		lean.AddNode(id)
+	}
+	for _, e := range g.sortedEdges() {
+		if !extra[e] {
+			lean.AddEdge(e.From, e.To)
+		}
+	}
+
+	layer, err := g.layerIndex()
+	if err != nil {
+		return []DeepRepo{}
+	}
+	minLayer, err := lean.layerIndex()
+	if err != nil {
+		return []DeepRepo{}
+	}
+
+	out := []DeepRepo{}
+	for _, id := range g.ordered {
+		if layer[id] <= minLayer[id] {
+			continue
+		}
+		d := DeepRepo{Repo: id, Layer: layer[id], MinLayer: minLayer[id]}
+		deps := g.DependenciesOf(id)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if extra[Edge{From: id, To: dep}] {
+				d.Extra = append(d.Extra, dep)
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// layerIndex maps every node to the index of its layer.
+func (g *Graph) layerIndex() (map[string]int, error) {
+	layers, err := g.Layers()
+	if err != nil {
+		return nil, err
+	}
+	idx := make(map[string]int, len(g.nodes))
+	for i, layer := range layers {
+		for _, id := range layer {
+			idx[id] = i
+		}
+	}
+	return idx, nil
+}
@@ -0,0 +1,268 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"gopkg.in/yaml.v3"
+)
+
+// OverlayFile is the name of the DAG overlay file, looked up in ~/.flywork
+// (global) and in <repos_path>/.flywork (per workspace).
+const OverlayFile = "dag.yaml"
+
+// BuildSettings are per-repository build overrides declared in an overlay.
+type BuildSettings struct {
+	// SkipTests overrides --skip-tests for this repo when set.
+	SkipTests *bool `yaml:"skip_tests,omitempty"`
+	// MavenArgs are appended to every Maven invocation for this repo.
+	MavenArgs []string `yaml:"maven_args,omitempty"`
+	// Goals replace the configured goals of install builds (e.g. verify).
+	Goals []string `yaml:"goals,omitempty"`
+	// Profiles are activated in addition to the configured ones.
+	Profiles []string `yaml:"profiles,omitempty"`
+	// Properties are passed as -D flags, overriding configured ones.
+	Properties map[string]string `yaml:"properties,omitempty"`
+	// Env sets environment variables of Maven, e.g. MAVEN_OPTS. An -Xmx
+	// there replaces the automatic heap budget but not --mem-per-job.
+	Env map[string]string `yaml:"env,omitempty"`
+	// Skip excludes the repo from build, install and publish (e.g. docs).
+	Skip bool `yaml:"skip,omitempty"`
+	// Publish set to false builds and installs the repo but never deploys it.
+	Publish *bool `yaml:"publish,omitempty"`
+	// DeployRepository overrides the Maven altDeploymentRepository used by
+	// publish, in the id::url form.
+	DeployRepository string `yaml:"deploy_repository,omitempty"`
+}
+
+// ResolveSkipTests returns the repo's skip_tests override, or def if unset.
+func (s BuildSettings) ResolveSkipTests(def bool) bool {
+	if s.SkipTests != nil {
+		return *s.SkipTests
+	}
+	return def
+}
+
+// Maven returns the repo's Maven settings, to be merged over the global
+// ones.
+func (s BuildSettings) Maven() maven.Settings {
+	return maven.Settings{
+		Goals:      s.Goals,
+		Profiles:   s.Profiles,
+		Properties: s.Properties,
+		Args:       s.MavenArgs,
+		Env:        s.Env,
+	}
+}
+
+// Publishable reports whether publish should deploy the repo.
+func (s BuildSettings) Publishable() bool {
+	return !s.Skip && (s.Publish == nil || *s.Publish)
+}
+
+// OverlayRepo declares a repository, or extends a framework repository,
+// in an overlay.
+type OverlayRepo struct {
+	Name      string        `yaml:"name"`
+	URL       string        `yaml:"url,omitempty"`
+	Branch    string        `yaml:"branch,omitempty"`
+	DependsOn []string      `yaml:"depends_on,omitempty"`
+	Build     BuildSettings `yaml:"build,omitempty"`
+}
+
+// Overlay adds nodes, edges, clone URLs and build settings on top of the
+// framework graph. Overlays are merged global first, then per workspace;
+// later files override the URL, branch and build settings of a repo declared
+// in an earlier one, while dependencies and edges accumulate.
+type Overlay struct {
+	Repos []OverlayRepo `yaml:"repos"`
+	Edges []Edge        `yaml:"edges"`
+
+	// Sources lists the overlay files that were merged, in order.
+	Sources []string `yaml:"-"`
+
+	index map[string]int
+}
+
+// OverlayPaths returns the overlay files consulted for the workspace at
+// reposDir, in merge order.
+func OverlayPaths(reposDir string) []string {
+	paths := []string{filepath.Join(config.FlyworkHome(), OverlayFile)}
+	if reposDir != "" {
+		paths = append(paths, filepath.Join(reposDir, config.FireflyDir, OverlayFile))
+	}
+	return paths
+}
+
+// LoadOverlay reads and merges the global and per-workspace overlay files.
+// Missing files are ignored; an empty overlay is returned when none exist.
+func LoadOverlay(reposDir string) (*Overlay, error) {
+	merged := &Overlay{index: make(map[string]int)}
+	for _, path := range OverlayPaths(reposDir) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var o Overlay
+		if err := yaml.Unmarshal(data, &o); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		for _, r := range o.Repos {
+			if r.Name == "" {
+				return nil, fmt.Errorf("%s: repository entry without a name", path)
+			}
+			merged.merge(r)
+		}
+		for _, e := range o.Edges {
+			if e.From == "" || e.To == "" {
+				return nil, fmt.Errorf("%s: edge needs both from and to", path)
+			}
+			merged.Edges = append(merged.Edges, e)
+		}
+		merged.Sources = append(merged.Sources, path)
+	}
+	return merged, nil
+}
+
+func (o *Overlay) merge(r OverlayRepo) {
+	i, ok := o.index[r.Name]
+	if !ok {
+		o.index[r.Name] = len(o.Repos)
+		o.Repos = append(o.Repos, r)
+		return
+	}
+	cur := &o.Repos[i]
+	if r.URL != "" {
+		cur.URL = r.URL
+	}
+	if r.Branch != "" {
+		cur.Branch = r.Branch
+	}
+	cur.DependsOn = append(cur.DependsOn, r.DependsOn...)
+	if r.Build.SkipTests != nil {
+		cur.Build.SkipTests = r.Build.SkipTests
+	}
+	if len(r.Build.MavenArgs) > 0 {
+		cur.Build.MavenArgs = r.Build.MavenArgs
+	}
+	if len(r.Build.Goals) > 0 {
+		cur.Build.Goals = r.Build.Goals
+	}
+	if len(r.Build.Profiles) > 0 {
+		cur.Build.Profiles = r.Build.Profiles
+	}
+	for k, v := range r.Build.Properties {
+		if cur.Build.Properties == nil {
+			cur.Build.Properties = make(map[string]string)
+		}
+		cur.Build.Properties[k] = v
+	}
+	for k, v := range r.Build.Env {
+		if cur.Build.Env == nil {
+			cur.Build.Env = make(map[string]string)
+		}
+		cur.Build.Env[k] = v
+	}
+	if r.Build.Skip {
+		cur.Build.Skip = true
+	}
+	if r.Build.Publish != nil {
+		cur.Build.Publish = r.Build.Publish
+	}
+	if r.Build.DeployRepository != "" {
+		cur.Build.DeployRepository = r.Build.DeployRepository
+	}
+}
+
+// Empty reports whether the overlay declares nothing.
+func (o *Overlay) Empty() bool {
+	return o == nil || (len(o.Repos) == 0 && len(o.Edges) == 0)
+}
+
+// Repo returns the overlay entry for a repository.
+func (o *Overlay) Repo(name string) (OverlayRepo, bool) {
+	if o == nil {
+		return OverlayRepo{}, false
+	}
+	i, ok := o.index[name]
+	if !ok {
+		return OverlayRepo{}, false
+	}
+	return o.Repos[i], true
+}
+
+// Settings returns the build settings of a repository (zero if undeclared).
+func (o *Overlay) Settings(name string) BuildSettings {
+	r, _ := o.Repo(name)
+	return r.Build
+}
+
+// Apply adds the overlay's repositories and edges to g. Every dependency must
+// name a repository that is either in g or declared by the overlay.
+func (o *Overlay) Apply(g *Graph) error {
+	if o.Empty() {
+		return nil
+	}
+	for _, r := range o.Repos {
+		g.AddNode(r.Name)
+	}
+	check := func(id, from string) error {
+		if !g.HasNode(id) {
+			return fmt.Errorf("dag overlay: %s depends on unknown repository %q", from, id)
+		}
+		return nil
+	}
+	for _, r := range o.Repos {
+		for _, dep := range r.DependsOn {
+			if err := check(dep, r.Name); err != nil {
+				return err
+			}
+			g.AddEdge(r.Name, dep)
+		}
+	}
+	for _, e := range o.Edges {
+		if !g.HasNode(e.From) {
+			return fmt.Errorf("dag overlay: edge from unknown repository %q", e.From)
+		}
+		if err := check(e.To, e.From); err != nil {
+			return err
+		}
+		g.AddEdge(e.From, e.To)
+	}
+	return nil
+}
+
+// Embedded returns the embedded FrameworkGraph with the overlays of the
+// workspace at reposDir applied. It is used where pom.xml files are not
+// available yet, e.g. when cloning.
+func Embedded(reposDir string) (*Graph, error) {
+	g := FrameworkGraph()
+	o, err := LoadOverlay(reposDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.Apply(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"sort"
+	"strings"
+)
+
+// AllPaths returns every simple dependency path from → ... → to, where each
+// repo in a path directly depends on the next one. Paths are ordered by
+// length, then lexicographically. It returns nil when from does not depend on
+// to (directly or transitively) or either node is unknown.
+func (g *Graph) AllPaths(from, to string) [][]string {
+	if !g.nodes[from] || !g.nodes[to] || from == to {
+		return nil
+	}
+
+	// Only descend into nodes that can still reach the target.
+	canReach := g.walk(to, g.reverse)
+	if !canReach[from] {
+		return nil
+	}
+
+	var paths [][]string
+	path := []string{from}
+	onPath := map[string]bool{from: true}
+
+	var visit func(node string)
+	visit = func(node string) {
+		if node == to {
+			paths = append(paths, append([]string(nil), path...))
+			return
+		}
+		deps := g.DependenciesOf(node)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if onPath[dep] || !canReach[dep] {
+				continue
+			}
+			onPath[dep] = true
+			path = append(path, dep)
+			visit(dep)
+			path = path[:len(path)-1]
+			delete(onPath, dep)
+		}
+	}
+	visit(from)
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return strings.Join(paths[i], "\x00") < strings.Join(paths[j], "\x00")
+	})
+	return paths
+}
+
+// ShortestPath returns a shortest dependency path from → ... → to, preferring
+// lexicographically smaller repos on ties. It returns nil when from does not
+// depend on to or either node is unknown.
+func (g *Graph) ShortestPath(from, to string) []string {
+	if !g.nodes[from] || !g.nodes[to] || from == to {
+		return nil
+	}
+
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		deps := g.DependenciesOf(node)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, seen := prev[dep]; seen {
+				continue
+			}
+			prev[dep] = node
+			if dep == to {
+				var path []string
+				for n := to; n != ""; n = prev[n] {
+					path = append([]string{n}, path...)
+				}
+				return path
+			}
+			queue = append(queue, dep)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Shard is the part of a layered build plan assigned to one CI runner.
+// Layers has one entry per layer of the full plan (possibly empty), so that
+// runners can synchronize on the same layer barriers: no runner may start
+// layer N+1 before every runner has finished layer N.
+type Shard struct {
+	Index    int        `json:"index"`
+	Shards   int        `json:"shards"`
+	Layers   [][]string `json:"layers"`
+	Repos    int        `json:"repos"`
+	Weight   float64    `json:"weight"`
+	Weighted bool       `json:"weighted"`
+}
+
+// ShardLayers partitions every layer across n shards. Repos are weighted by
+// weights (e.g. historical build seconds); repos without a weight get the
+// average of the known weights, or 1 when none are known. Within each layer
+// the heaviest repos are assigned first to the least loaded shard, which
+// keeps the slowest shard of every layer — the layer's wall time — short.
+func ShardLayers(layers [][]string, n int, weights map[string]float64) ([]Shard, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("shard count must be at least 1, got %d", n)
+	}
+
+	fallback, known := 1.0, 0
+	var sum float64
+	for _, layer := range layers {
+		for _, repo := range layer {
+			if w, ok := weights[repo]; ok && w > 0 {
+				sum += w
+				known++
+			}
+		}
+	}
+	if known > 0 {
+		fallback = sum / float64(known)
+	}
+	weightOf := func(repo string) float64 {
+		if w, ok := weights[repo]; ok && w > 0 {
+			return w
+		}
+		return fallback
+	}
+
+	shards := make([]Shard, n)
+	for i := range shards {
+		shards[i] = Shard{Index: i, Shards: n, Layers: make([][]string, len(layers)), Weighted: known > 0}
+		for l := range layers {
+			shards[i].Layers[l] = []string{}
+		}
+	}
+
+	for l, layer := range layers {
+		repos := append([]string(nil), layer...)
+		sort.SliceStable(repos, func(i, j int) bool {
+			wi, wj := weightOf(repos[i]), weightOf(repos[j])
+			if wi != wj {
+				return wi > wj
+			}
+			return repos[i] < repos[j]
+		})
+
+		load := make([]float64, n)
+		for _, repo := range repos {
+			best := 0
+			for i := 1; i < n; i++ {
+				// Least loaded in this layer; ties go to the shard with the
+				// least total work so far, then the lowest index.
+				if load[i] < load[best] || (load[i] == load[best] && shards[i].Weight < shards[best].Weight) {
+					best = i
+				}
+			}
+			w := weightOf(repo)
+			load[best] += w
+			shards[best].Layers[l] = append(shards[best].Layers[l], repo)
+			shards[best].Weight += w
+			shards[best].Repos++
+		}
+	}
+
+	return shards, nil
+}
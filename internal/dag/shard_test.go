@@ -0,0 +1,167 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestShardLayers(t *testing.T) {
+	tests := []struct {
+		name    string
+		layers  [][]string
+		n       int
+		weights map[string]float64
+		want    [][][]string // shard → layer → repos
+		weight  []float64
+	}{
+		{
+			name:    "heaviest first to the least loaded shard",
+			layers:  [][]string{{"a", "b", "c", "d"}},
+			n:       2,
+			weights: map[string]float64{"a": 10, "b": 6, "c": 4, "d": 2},
+			want:    [][][]string{{{"a", "d"}}, {{"b", "c"}}},
+			weight:  []float64{12, 10},
+		},
+		{
+			name:    "unknown weights get the average",
+			layers:  [][]string{{"a", "b", "c"}},
+			n:       2,
+			weights: map[string]float64{"a": 4, "b": 2},
+			want:    [][][]string{{{"a"}}, {{"c", "b"}}},
+			weight:  []float64{4, 5},
+		},
+		{
+			name:   "unweighted repos spread by count",
+			layers: [][]string{{"a", "b", "c"}, {"d", "e"}},
+			n:      2,
+			want:   [][][]string{{{"a", "c"}, {"e"}}, {{"b"}, {"d"}}},
+			weight: []float64{3, 2},
+		},
+		{
+			name:   "more shards than repos",
+			layers: [][]string{{"a"}, {"b"}},
+			n:      3,
+			want:   [][][]string{{{"a"}, {}}, {{}, {"b"}}, {{}, {}}},
+			weight: []float64{1, 1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shards, err := ShardLayers(tt.layers, tt.n, tt.weights)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(shards) != tt.n {
+				t.Fatalf("got %d shards, want %d", len(shards), tt.n)
+			}
+			for i, s := range shards {
+				if s.Index != i || s.Shards != tt.n {
+					t.Errorf("shard %d: index %d of %d", i, s.Index, s.Shards)
+				}
+				if !reflect.DeepEqual(s.Layers, tt.want[i]) {
+					t.Errorf("shard %d: layers %v, want %v", i, s.Layers, tt.want[i])
+				}
+				if s.Weight != tt.weight[i] {
+					t.Errorf("shard %d: weight %v, want %v", i, s.Weight, tt.weight[i])
+				}
+				if s.Weighted != (len(tt.weights) > 0) {
+					t.Errorf("shard %d: weighted %v", i, s.Weighted)
+				}
+			}
+		})
+	}
+}
+
+func TestShardLayersCoversEveryRepoOnce(t *testing.T) {
+	g := FrameworkGraph()
+	layers, err := g.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 1; n <= 5; n++ {
+		shards, err := ShardLayers(layers, n, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := map[string]int{}
+		for _, s := range shards {
+			if len(s.Layers) != len(layers) {
+				t.Fatalf("n=%d: shard %d has %d layers, want %d", n, s.Index, len(s.Layers), len(layers))
+			}
+			count := 0
+			for l, repos := range s.Layers {
+				for _, repo := range repos {
+					if !slices.Contains(layers[l], repo) {
+						t.Errorf("n=%d: %s assigned to layer %d", n, repo, l)
+					}
+					seen[repo]++
+					count++
+				}
+			}
+			if count != s.Repos {
+				t.Errorf("n=%d: shard %d counts %d repos, holds %d", n, s.Index, s.Repos, count)
+			}
+		}
+		if len(seen) != g.NodeCount() {
+			t.Errorf("n=%d: %d repos assigned, want %d", n, len(seen), g.NodeCount())
+		}
+		for repo, c := range seen {
+			if c != 1 {
+				t.Errorf("n=%d: %s assigned %d times", n, repo, c)
+			}
+		}
+
+		// Without weights every layer is split evenly.
+		for l := range layers {
+			lo, hi := len(layers[l]), 0
+			for _, s := range shards {
+				lo, hi = min(lo, len(s.Layers[l])), max(hi, len(s.Layers[l]))
+			}
+			if hi-lo > 1 {
+				t.Errorf("n=%d: layer %d split %d..%d", n, l, lo, hi)
+			}
+		}
+	}
+}
+
+func TestShardLayersDeterministic(t *testing.T) {
+	layers := [][]string{{"a", "b", "c", "d", "e"}, {"f", "g", "h"}}
+	reversed := [][]string{{"e", "d", "c", "b", "a"}, {"h", "g", "f"}}
+	weights := map[string]float64{"a": 3, "b": 3, "c": 1, "f": 2, "g": 2}
+
+	first, err := ShardLayers(layers, 3, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range [][][]string{layers, reversed} {
+		again, err := ShardLayers(in, 3, weights)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(first, again) {
+			t.Errorf("ShardLayers(%v) = %+v, want %+v", in, again, first)
+		}
+	}
+}
+
+func TestShardLayersInvalidCount(t *testing.T) {
+	if _, err := ShardLayers([][]string{{"a"}}, 0, nil); err == nil {
+		t.Error("ShardLayers accepted 0 shards")
+	}
+}
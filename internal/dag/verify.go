@@ -0,0 +1,120 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import "sort"
+
+// Edge is a single dependency edge: From depends on To.
+type Edge struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// MissingEdge is a dependency declared in a pom.xml but absent from the graph.
+// Implied is true when the graph still orders the repos correctly through a
+// transitive path, so only the direct edge is missing.
+type MissingEdge struct {
+	Edge
+	Implied bool `json:"implied"`
+}
+
+// Drift describes the differences between a graph and the dependencies
+// declared in the workspace pom.xml files.
+type Drift struct {
+	Missing   []MissingEdge       `json:"missing_edges"`
+	Extra     []Edge              `json:"extra_edges"`
+	Unknown   map[string][]string `json:"unknown_artifacts"`
+	Untracked []string            `json:"untracked_repos"`
+	Conflicts map[string][]string `json:"conflicting_artifacts"`
+	Invalid   map[string]string   `json:"invalid_poms"`
+	Checked   int                 `json:"repos_checked"`
+}
+
+// HasDrift reports whether the graph disagrees with the workspace in a way
+// that can produce a wrong build order or hides unattributed artifacts,
+// including artifacts declared by several repos and unparsable root poms.
+// Extra edges only slow builds down and are not considered drift here.
+func (d *Drift) HasDrift() bool {
+	for _, m := range d.Missing {
+		if !m.Implied {
+			return true
+		}
+	}
+	return len(d.Unknown) > 0 || len(d.Untracked) > 0 || len(d.Conflicts) > 0 || len(d.Invalid) > 0
+}
+
+// Verify compares the edges of expected against the dependencies declared in
+// the workspace. Only cloned repositories are checked.
+func Verify(expected *Graph, ws *Workspace) *Drift {
+	d := &Drift{
+		Missing:   []MissingEdge{},
+		Extra:     []Edge{},
+		Unknown:   make(map[string][]string),
+		Untracked: []string{},
+		Conflicts: ws.Conflicts,
+		Invalid:   ws.Invalid,
+		Checked:   len(ws.Repos),
+	}
+
+	for _, repo := range ws.Repos {
+		if !expected.HasNode(repo) {
+			d.Untracked = append(d.Untracked, repo)
+		}
+
+		declared := ws.Deps[repo]
+		for _, dep := range sortedKeys(declared) {
+			if !expected.edges[repo][dep] {
+				d.Missing = append(d.Missing, MissingEdge{
+					Edge:    Edge{From: repo, To: dep},
+					Implied: expected.reaches(repo, dep),
+				})
+			}
+		}
+
+		deps := expected.DependenciesOf(repo)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if !declared[dep] {
+				d.Extra = append(d.Extra, Edge{From: repo, To: dep})
+			}
+		}
+
+		if unknown := ws.Unknown[repo]; len(unknown) > 0 {
+			d.Unknown[repo] = unknown
+		}
+	}
+
+	return d
+}
+
+// reaches reports whether from transitively depends on to.
+func (g *Graph) reaches(from, to string) bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for dep := range g.edges[node] {
+			if dep == to {
+				return true
+			}
+			if !visited[dep] {
+				visited[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return false
+}
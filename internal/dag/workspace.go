@@ -0,0 +1,435 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FrameworkGroupID is the Maven groupId shared by every framework artifact.
+const FrameworkGroupID = "org.fireflyframework"
+
+// pomCoord is a Maven coordinate as it appears in <parent>, <dependency> or
+// <plugin> elements.
+type pomCoord struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+}
+
+// pomManaged is a <dependencyManagement> entry. Only BOM imports (scope
+// import, type pom) are build-order constraints; other entries merely pin
+// versions.
+type pomManaged struct {
+	pomCoord
+	Scope string `xml:"scope"`
+	Type  string `xml:"type"`
+}
+
+func (m pomManaged) isImport() bool {
+	return strings.TrimSpace(m.Scope) == "import" && strings.TrimSpace(m.Type) == "pom"
+}
+
+type pomPlugin struct {
+	GroupID      string     `xml:"groupId"`
+	ArtifactID   string     `xml:"artifactId"`
+	Dependencies []pomCoord `xml:"dependencies>dependency"`
+}
+
+type pomProfile struct {
+	Modules              []string     `xml:"modules>module"`
+	Dependencies         []pomCoord   `xml:"dependencies>dependency"`
+	DependencyManagement []pomManaged `xml:"dependencyManagement>dependencies>dependency"`
+}
+
+// pomProject is the subset of a pom.xml needed to derive framework dependencies.
+type pomProject struct {
+	XMLName              xml.Name     `xml:"project"`
+	Parent               pomCoord     `xml:"parent"`
+	GroupID              string       `xml:"groupId"`
+	ArtifactID           string       `xml:"artifactId"`
+	Modules              []string     `xml:"modules>module"`
+	Dependencies         []pomCoord   `xml:"dependencies>dependency"`
+	DependencyManagement []pomManaged `xml:"dependencyManagement>dependencies>dependency"`
+	Plugins              []pomPlugin  `xml:"build>plugins>plugin"`
+	Profiles             []pomProfile `xml:"profiles>profile"`
+}
+
+// groupID returns the effective groupId, inherited from the parent if unset.
+func (p *pomProject) groupID() string {
+	if p.GroupID != "" {
+		return p.GroupID
+	}
+	return p.Parent.GroupID
+}
+
+// references returns every coordinate the POM needs built first: its parent,
+// dependencies, BOM imports and build plugins. Other managed dependencies are
+// left out — a parent or BOM manages nearly every framework artifact without
+// depending on them.
+func (p *pomProject) references() []pomCoord {
+	refs := []pomCoord{p.Parent}
+	refs = append(refs, p.Dependencies...)
+	refs = appendImports(refs, p.DependencyManagement)
+	for _, pl := range p.Plugins {
+		refs = append(refs, pomCoord{GroupID: pl.GroupID, ArtifactID: pl.ArtifactID})
+		refs = append(refs, pl.Dependencies...)
+	}
+	for _, pr := range p.Profiles {
+		refs = append(refs, pr.Dependencies...)
+		refs = appendImports(refs, pr.DependencyManagement)
+	}
+	return refs
+}
+
+// appendImports appends the coordinates of the BOM imports among managed.
+func appendImports(refs []pomCoord, managed []pomManaged) []pomCoord {
+	for _, m := range managed {
+		if m.isImport() {
+			refs = append(refs, m.pomCoord)
+		}
+	}
+	return refs
+}
+
+// modules returns the declared submodules, including those inside profiles.
+func (p *pomProject) modules() []string {
+	mods := append([]string{}, p.Modules...)
+	for _, pr := range p.Profiles {
+		mods = append(mods, pr.Modules...)
+	}
+	return mods
+}
+
+// resolveGroup expands the ${project.*} placeholders commonly used for the
+// groupId of sibling artifacts.
+func (p *pomProject) resolveGroup(group string) string {
+	switch strings.TrimSpace(group) {
+	case "${project.groupId}", "${pom.groupId}", "${groupId}":
+		return p.groupID()
+	case "${project.parent.groupId}":
+		return p.Parent.GroupID
+	}
+	return strings.TrimSpace(group)
+}
+
+// Workspace holds the framework dependencies declared in the pom.xml files of
+// the repositories cloned under a directory.
+type Workspace struct {
+	Dir   string
+	Repos []string // repos with a framework root pom.xml, sorted by name
+
+	// Owners maps every framework artifactId found in the workspace to the
+	// repository whose root or submodule pom.xml declares it. An artifactId
+	// declared by several repos belongs to the first by name; see Conflicts.
+	Owners map[string]string
+
+	// Conflicts maps an artifactId declared by more than one repository to
+	// those repositories, sorted by name.
+	Conflicts map[string][]string
+
+	// Invalid maps a repo whose root pom.xml cannot be parsed to the parse
+	// error. Such repos are left out of the scan, so Load falls back to
+	// their edges in the embedded graph.
+	Invalid map[string]string
+
+	// Deps maps a repo to the repos it depends on, according to its poms.
+	Deps map[string]map[string]bool
+
+	// Unknown maps a repo to the framework artifactIds it references that
+	// could not be attributed to any repository.
+	Unknown map[string][]string
+
+	poms  map[string][]*pomProject
+	known map[string]bool
+}
+
+// ScanWorkspace parses the root and submodule pom.xml files of every framework
+// repository under reposDir and resolves their org.fireflyframework references
+// back to the owning repositories. Repos with an unparsable root pom.xml are
+// recorded in Invalid rather than failing the scan.
+func ScanWorkspace(reposDir string) (*Workspace, error) {
+	entries, err := os.ReadDir(reposDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &Workspace{
+		Dir:       reposDir,
+		Owners:    make(map[string]string),
+		Conflicts: make(map[string][]string),
+		Invalid:   make(map[string]string),
+		Deps:      make(map[string]map[string]bool),
+		Unknown:   make(map[string][]string),
+		poms:      make(map[string][]*pomProject),
+		known:     make(map[string]bool),
+	}
+
+	// Pass 1: collect every framework repo and the artifacts it owns. Entries
+	// are sorted by name, so the owner of a conflicting artifact is stable.
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		repo := e.Name()
+		repoDir := filepath.Join(reposDir, repo)
+		root, err := readPom(filepath.Join(repoDir, "pom.xml"))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				ws.Invalid[repo] = err.Error()
+			}
+			continue
+		}
+		if root.groupID() != FrameworkGroupID {
+			continue
+		}
+
+		poms := []*pomProject{root}
+		poms = append(poms, readModules(repoDir, root, map[string]bool{repoDir: true})...)
+
+		ws.Repos = append(ws.Repos, repo)
+		ws.poms[repo] = poms
+		ws.known[repo] = true
+		for _, p := range poms {
+			if p.ArtifactID == "" || p.groupID() != FrameworkGroupID {
+				continue
+			}
+			owner, ok := ws.Owners[p.ArtifactID]
+			switch {
+			case !ok:
+				ws.Owners[p.ArtifactID] = repo
+			case owner != repo && !slices.Contains(ws.Conflicts[p.ArtifactID], repo):
+				if len(ws.Conflicts[p.ArtifactID]) == 0 {
+					ws.Conflicts[p.ArtifactID] = []string{owner}
+				}
+				ws.Conflicts[p.ArtifactID] = append(ws.Conflicts[p.ArtifactID], repo)
+			}
+		}
+	}
+
+	// Repo names from the embedded graph help attribute artifacts of repos
+	// that are not cloned (e.g. fireflyframework-starter-core → core).
+	for _, n := range FrameworkGraph().Nodes() {
+		ws.known[n] = true
+	}
+
+	// Pass 2: resolve framework references to repo-level dependencies.
+	for _, repo := range ws.Repos {
+		deps := make(map[string]bool)
+		unknown := make(map[string]bool)
+		for _, p := range ws.poms[repo] {
+			for _, ref := range p.references() {
+				if ref.ArtifactID == "" || p.resolveGroup(ref.GroupID) != FrameworkGroupID {
+					continue
+				}
+				target, ok := ws.Resolve(ref.ArtifactID)
+				if !ok {
+					unknown[ref.ArtifactID] = true
+					continue
+				}
+				if target != repo {
+					deps[target] = true
+				}
+			}
+		}
+		ws.Deps[repo] = deps
+		if len(unknown) > 0 {
+			ws.Unknown[repo] = sortedKeys(unknown)
+		}
+	}
+
+	return ws, nil
+}
+
+// Resolve maps a framework artifactId to the repository that produces it.
+// Artifacts declared in a cloned pom.xml resolve exactly; otherwise starter
+// style names (fireflyframework-starter-X, fireflyframework-X-starter) and
+// submodule names (fireflyframework-X-suffix) are matched against known repos.
+func (w *Workspace) Resolve(artifactID string) (string, bool) {
+	if repo, ok := w.Owners[artifactID]; ok {
+		return repo, true
+	}
+	for _, candidate := range artifactCandidates(artifactID) {
+		if w.known[candidate] {
+			return candidate, true
+		}
+	}
+
+	// Longest known repo name that prefixes one of the candidates.
+	best := ""
+	for _, candidate := range artifactCandidates(artifactID) {
+		for repo := range w.known {
+			if strings.HasPrefix(candidate, repo+"-") && len(repo) > len(best) {
+				best = repo
+			}
+		}
+	}
+	return best, best != ""
+}
+
+// Graph builds a dependency graph from the workspace. Nodes keep the order of
+// the embedded FrameworkGraph where possible so that output stays familiar.
+func (w *Workspace) Graph() *Graph {
+	g := New()
+	cloned := make(map[string]bool, len(w.Repos))
+	for _, repo := range w.Repos {
+		cloned[repo] = true
+	}
+	for _, n := range FrameworkGraph().Nodes() {
+		if cloned[n] {
+			g.AddNode(n)
+		}
+	}
+	for _, repo := range w.Repos {
+		g.AddNode(repo)
+	}
+	for _, repo := range w.Repos {
+		for _, dep := range sortedKeys(w.Deps[repo]) {
+			g.AddEdge(repo, dep)
+		}
+	}
+	return g
+}
+
+// FromWorkspace derives the framework dependency graph from the pom.xml files
+// of the repositories cloned under reposDir.
+func FromWorkspace(reposDir string) (*Graph, error) {
+	ws, err := ScanWorkspace(reposDir)
+	if err != nil {
+		return nil, err
+	}
+	return ws.Graph(), nil
+}
+
+// Load returns the dependency graph for the workspace at reposDir. Cloned
+// repositories contribute the edges declared in their pom.xml files; repos
+// that are not cloned yet keep their edges from the embedded FrameworkGraph.
+// When nothing is cloned, FrameworkGraph is used as a bootstrap fallback.
+// The global and per-workspace overlays (dag.yaml) are applied last.
+func Load(reposDir string) (*Graph, error) {
+	overlay, err := LoadOverlay(reposDir)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := LoadFramework(reposDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := overlay.Apply(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// LoadFramework is Load without the overlays: the graph contains framework
+// repositories only. It is used where team-owned repos must not take part,
+// such as framework-wide version management.
+func LoadFramework(reposDir string) (*Graph, error) {
+	ws, err := ScanWorkspace(reposDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return FrameworkGraph(), nil
+		}
+		return nil, fmt.Errorf("failed to scan workspace %s: %w", reposDir, err)
+	}
+	if len(ws.Repos) == 0 {
+		return FrameworkGraph(), nil
+	}
+
+	g := ws.Graph()
+	embedded := FrameworkGraph()
+	for _, n := range embedded.Nodes() {
+		if _, ok := ws.poms[n]; ok {
+			continue
+		}
+		g.AddNode(n)
+		deps := embedded.DependenciesOf(n)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			g.AddEdge(n, dep)
+		}
+	}
+	return g, nil
+}
+
+// artifactCandidates returns the artifactId followed by its starter-less forms.
+func artifactCandidates(artifactID string) []string {
+	out := []string{artifactID}
+	prefix := "fireflyframework-"
+	if rest, ok := strings.CutPrefix(artifactID, prefix+"starter-"); ok {
+		out = append(out, prefix+rest)
+	}
+	for _, marker := range []string{"-spring-boot-starter", "-starter"} {
+		if i := strings.Index(artifactID, marker); i > len(prefix) {
+			out = append(out, artifactID[:i])
+		}
+	}
+	return out
+}
+
+// readPom parses a single pom.xml file.
+func readPom(path string) (*pomProject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p pomProject
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// readModules recursively loads the submodule poms declared by parent.
+// Missing or unparsable submodules are ignored.
+func readModules(dir string, parent *pomProject, seen map[string]bool) []*pomProject {
+	var out []*pomProject
+	for _, mod := range parent.modules() {
+		mod = strings.TrimSpace(mod)
+		modDir := filepath.Join(dir, mod)
+		pomPath := filepath.Join(modDir, "pom.xml")
+		if strings.HasSuffix(mod, ".xml") {
+			pomPath = modDir
+			modDir = filepath.Dir(modDir)
+		}
+		if seen[modDir] {
+			continue
+		}
+		seen[modDir] = true
+
+		p, err := readPom(pomPath)
+		if err != nil {
+			continue
+		}
+		out = append(out, p)
+		out = append(out, readModules(modDir, p, seen)...)
+	}
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -25,7 +25,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/java"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
 	"github.com/fireflyframework/fireflyframework-cli/internal/setup"
@@ -74,6 +76,7 @@ func RunGlobal(cfg *config.Config) []ui.CheckResult {
 	results = append(results, checkDocker())
 	results = append(results, checkFlyworkConfig())
 	results = append(results, checkReposCloned(cfg))
+	results = append(results, checkCatalog(cfg))
 	results = append(results, checkParentPOM())
 	results = append(results, checkBOM())
 	results = append(results, checkSetupManifest())
@@ -191,9 +194,13 @@ func checkReposCloned(cfg *config.Config) ui.CheckResult {
 	if cfg == nil {
 		return ui.CheckResult{Name: "Framework repos", Status: "warn", Detail: "config not loaded"}
 	}
-	total := len(setup.FrameworkRepos)
+	cat, err := catalog.Load(cfg.ReposPath)
+	if err != nil {
+		return ui.CheckResult{Name: "Framework repos", Status: "fail", Detail: "repository catalog: " + err.Error()}
+	}
+	total := len(cat.Repos)
 	cloned := 0
-	for _, repo := range setup.FrameworkRepos {
+	for _, repo := range cat.Names() {
 		repoDir := filepath.Join(cfg.ReposPath, repo)
 		if _, err := os.Stat(repoDir); err == nil {
 			cloned++
@@ -208,6 +215,35 @@ func checkReposCloned(cfg *config.Config) ui.CheckResult {
 	return ui.CheckResult{Name: "Framework repos", Status: "pass", Detail: fmt.Sprintf("%d/%d", cloned, total)}
 }
 
+func checkCatalog(cfg *config.Config) ui.CheckResult {
+	if cfg == nil {
+		return ui.CheckResult{Name: "Repo catalog", Status: "warn", Detail: "config not loaded"}
+	}
+	cat, err := catalog.Load(cfg.ReposPath)
+	if err != nil {
+		return ui.CheckResult{Name: "Repo catalog", Status: "fail", Detail: err.Error()}
+	}
+	g, err := dag.Embedded(cfg.ReposPath)
+	if err != nil {
+		return ui.CheckResult{Name: "Repo catalog", Status: "fail", Detail: "dependency graph: " + err.Error()}
+	}
+	m := cat.Check(g)
+	if !m.Empty() {
+		var parts []string
+		if len(m.NotInGraph) > 0 {
+			parts = append(parts, fmt.Sprintf("not in DAG: %s", strings.Join(m.NotInGraph, ", ")))
+		}
+		if len(m.NotInCatalog) > 0 {
+			parts = append(parts, fmt.Sprintf("not in catalog: %s", strings.Join(m.NotInCatalog, ", ")))
+		}
+		if len(m.NotMaven) > 0 {
+			parts = append(parts, fmt.Sprintf("in DAG but not Maven: %s", strings.Join(m.NotMaven, ", ")))
+		}
+		return ui.CheckResult{Name: "Repo catalog", Status: "warn", Detail: strings.Join(parts, "; ")}
+	}
+	return ui.CheckResult{Name: "Repo catalog", Status: "pass", Detail: fmt.Sprintf("%d repos, consistent with DAG", len(cat.Repos))}
+}
+
 func checkMaven() ui.CheckResult {
 	ver, err := maven.Version()
 	if err != nil {
@@ -15,10 +15,13 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/proc"
 )
 
 // IsInstalled checks if git is available on PATH.
@@ -51,14 +54,15 @@ func Clone(repoURL, targetDir, branch string) error {
 }
 
 // CloneQuiet clones a repository without terminal output.
-// If branch is non-empty, that branch is checked out.
-func CloneQuiet(repoURL, targetDir, branch string) error {
+// If branch is non-empty, that branch is checked out. Cancelling ctx stops
+// the clone, leaving a partial targetDir behind.
+func CloneQuiet(ctx context.Context, repoURL, targetDir, branch string) error {
 	args := []string{"clone", "--quiet"}
 	if branch != "" {
 		args = append(args, "--branch", branch)
 	}
 	args = append(args, repoURL, targetDir)
-	cmd := exec.Command("git", args...)
+	cmd := proc.Command(ctx, "git", args...)
 	return cmd.Run()
 }
 
@@ -83,16 +87,17 @@ func Commit(dir, message string) error {
 	return cmd.Run()
 }
 
-// Pull performs a git pull in the given directory.
-func Pull(dir string) error {
-	cmd := exec.Command("git", "pull", "--quiet")
+// Pull performs a git pull in the given directory. Cancelling ctx stops it.
+func Pull(ctx context.Context, dir string) error {
+	cmd := proc.Command(ctx, "git", "pull", "--quiet")
 	cmd.Dir = dir
 	return cmd.Run()
 }
 
-// FetchQuiet runs git fetch --quiet in the given directory.
-func FetchQuiet(dir string) error {
-	cmd := exec.Command("git", "fetch", "--quiet")
+// FetchQuiet runs git fetch --quiet in the given directory. Cancelling ctx
+// stops it.
+func FetchQuiet(ctx context.Context, dir string) error {
+	cmd := proc.Command(ctx, "git", "fetch", "--quiet")
 	cmd.Dir = dir
 	return cmd.Run()
 }
@@ -162,6 +167,36 @@ func HeadSHA(dir string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// WorkingTreeChanges returns the paths, relative to dir, of the tracked files
+// with uncommitted changes (staged or not, including deletions and both sides
+// of renames) and of the untracked files that .gitignore does not exclude.
+func WorkingTreeChanges(dir string) ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		// Renames and copies are followed by the original path.
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+			if i < len(entries) && entries[i] != "" {
+				paths = append(paths, entries[i])
+			}
+		}
+	}
+	return paths, nil
+}
+
 // DiffStatSince returns the list of files changed between sinceCommit and HEAD.
 func DiffStatSince(dir, sinceCommit string) ([]string, error) {
 	cmd := exec.Command("git", "diff", "--name-only", sinceCommit, "HEAD")
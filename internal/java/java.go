@@ -43,6 +43,37 @@ func CurrentVersion() (int, error) {
 	return parseMajorVersion(string(out))
 }
 
+// RuntimeVersion identifies the JDK at javaHome (or the java on PATH when
+// javaHome is empty), e.g. "Eclipse Adoptium 25.0.1". It reads the
+// JAVA_VERSION and IMPLEMENTOR entries of the JDK's release file and falls
+// back to the first line of `java -version`. It returns "" if no JDK is found.
+func RuntimeVersion(javaHome string) string {
+	if javaHome != "" {
+		if data, err := os.ReadFile(filepath.Join(javaHome, "release")); err == nil {
+			fields := map[string]string{}
+			for _, line := range strings.Split(string(data), "\n") {
+				if k, v, ok := strings.Cut(line, "="); ok {
+					fields[k] = strings.Trim(strings.TrimSpace(v), `"`)
+				}
+			}
+			if v := fields["JAVA_VERSION"]; v != "" {
+				return strings.TrimSpace(fields["IMPLEMENTOR"] + " " + v)
+			}
+		}
+	}
+
+	javaBin := "java"
+	if javaHome != "" {
+		javaBin = filepath.Join(javaHome, "bin", "java")
+	}
+	out, err := exec.Command(javaBin, "-version").CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(line)
+}
+
 // DetectJavaHome finds the JAVA_HOME for a specific major version.
 // It tries platform-specific discovery, then falls back to JAVA_HOME env var.
 func DetectJavaHome(version string) (string, error) {
@@ -0,0 +1,372 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Diagnosis is what the output of a failed Maven build says went wrong.
+type Diagnosis struct {
+	Module           string          `json:"module,omitempty"` // Project whose goal failed (artifactId)
+	Goal             string          `json:"goal,omitempty"`   // Failed plugin goal, e.g. maven-compiler-plugin:3.13.0:compile
+	Message          string          `json:"message,omitempty"`
+	CompilerErrors   []CompilerError `json:"compiler_errors,omitempty"`
+	FailedTests      []string        `json:"failed_tests,omitempty"`      // Test classes reported by Surefire or Failsafe
+	MissingArtifacts []string        `json:"missing_artifacts,omitempty"` // Coordinates that could not be resolved
+	ParentPOM        string          `json:"parent_pom,omitempty"`        // Unresolvable parent POM, if any
+	EnforcerRules    []string        `json:"enforcer_rules,omitempty"`    // "Rule: message" of each violated rule
+	// Hints are remediations for known failure signatures. Those that
+	// suggest a flywork command for the repo being built refer to it as
+	// "<repo>".
+	Hints []string `json:"hints,omitempty"`
+}
+
+// CompilerError is one error reported by javac or kotlinc.
+type CompilerError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e CompilerError) String() string {
+	return fmt.Sprintf("%s:%d %s", e.File, e.Line, e.Message)
+}
+
+var (
+	// "[ERROR] Failed to execute goal org.apache.maven.plugins:maven-compiler-plugin:3.13.0:compile
+	// (default-compile) on project core-web: Compilation failure"; dependency
+	// resolution failures have no goal.
+	failedGoalRe = regexp.MustCompile(`Failed to execute goal (?:(\S+) (?:\([^)]*\) )?)?on project ([^:\s]+): ?(.*)`)
+	// "[ERROR] /repo/src/main/java/Foo.java:[12,8] cannot find symbol"
+	javacErrorRe = regexp.MustCompile(`^\[ERROR\] (\S.*?\.java):\[(\d+),(\d+)\] (.+)$`)
+	// "[ERROR] e: file:///repo/src/main/kotlin/Foo.kt:12:5 Unresolved reference: bar"
+	kotlincErrorRe = regexp.MustCompile(`^(?:\[ERROR\] )?e: (?:file://)?(\S.*?\.kts?):(\d+):(\d+) (.+)$`)
+	// "Tests run: 3, Failures: 1, Errors: 0, Skipped: 0, Time elapsed: 0.2 s <<< FAILURE! -- in com.acme.FooTest"
+	testClassRe = regexp.MustCompile(`<<< (?:FAILURE|ERROR)!\s+-+\s+in\s+(\S+)`)
+	// "[ERROR]   FooTest.shouldWork:42 expected: <1> but was: <2>" in the
+	// "Failures:" and "Errors:" sections of the Surefire summary
+	testMethodRe = regexp.MustCompile(`^\[ERROR\]\s{2,}(?:Run \d+: )?([\w$.]+)\.[\w$]+(?::\d+)?(?:\s|$)`)
+	// "[ERROR] com.acme.FooTest.shouldWork" heading the "Run 1:", "Run 2:"
+	// lines of a test rerun with rerunFailingTestsCount
+	rerunTestRe = regexp.MustCompile(`^\[ERROR\] ([\w$]+(?:\.[\w$]+)+)\.[\w$]+$`)
+	// Dependency resolution messages naming the coordinates they could not resolve.
+	missingArtifactRe = regexp.MustCompile(`(?:Could not find artifact|Failure to find|Could not transfer artifact) (\S+)`)
+	unresolvedListRe  = regexp.MustCompile(`The following artifacts could not be resolved: (.+?)(?:: (?:Could not|Failure to)|$)`)
+	coordinatesRe     = regexp.MustCompile(`[\w.\-]+:[\w.\-]+(?::[\w.\-]+){1,3}`)
+	parentPOMRe       = regexp.MustCompile(`Non-resolvable parent POM for [^:]+:[^:]+:[^:\s]+: (?:Could not find artifact |Failure to find )?([\w.\-]+:[\w.\-]+(?::[\w.\-]+){1,2})`)
+	// "Rule 0: org.apache.maven.enforcer.rules.version.RequireJavaVersion failed with message:"
+	enforcerRuleRe = regexp.MustCompile(`Rule \d+: (\S+) failed with message:\s*(.*)$`)
+	logLevelRe     = regexp.MustCompile(`^\[(?:ERROR|WARNING|WARN|INFO|FATAL)\]\s?`)
+	// "error: release version 25 not supported"
+	releaseNotSupportedRe = regexp.MustCompile(`release version \d+ not supported`)
+)
+
+// Analyze extracts the failing module, compiler errors, failed test classes,
+// unresolved artifacts and enforcer violations from the output of a failed
+// Maven build, and adds remediation hints for known failure signatures. It
+// returns nil when the output contains none of them.
+func Analyze(output []byte) *Diagnosis {
+	d := &Diagnosis{}
+	seen := make(map[string]bool)
+	add := func(list *[]string, s string) {
+		for _, existing := range *list {
+			if existing == s {
+				return
+			}
+		}
+		*list = append(*list, s)
+	}
+
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(output))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimRight(sc.Text(), "\r"))
+	}
+
+	inTestSummary := false
+	for i, line := range lines {
+		if m := failedGoalRe.FindStringSubmatch(line); m != nil && d.Module == "" {
+			d.Goal, d.Module, d.Message = shortGoal(m[1]), m[2], strings.TrimSpace(m[3])
+		}
+
+		if e, ok := compilerError(line); ok {
+			// javac explains unresolved symbols on the following lines.
+			if i+1 < len(lines) {
+				if sym := strings.TrimSpace(logLevelRe.ReplaceAllString(lines[i+1], "")); strings.HasPrefix(sym, "symbol:") {
+					e.Message += " (" + strings.Join(strings.Fields(sym), " ") + ")"
+				}
+			}
+			key := e.String()
+			if !seen[key] {
+				seen[key] = true
+				d.CompilerErrors = append(d.CompilerErrors, e)
+			}
+		}
+
+		if m := testClassRe.FindStringSubmatch(line); m != nil {
+			add(&d.FailedTests, m[1])
+		}
+		switch strings.TrimSpace(logLevelRe.ReplaceAllString(line, "")) {
+		case "Failures:", "Errors:":
+			inTestSummary = true
+			continue
+		}
+		if inTestSummary {
+			if m := testMethodRe.FindStringSubmatch(line); m != nil {
+				add(&d.FailedTests, m[1])
+			} else if m := rerunTestRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				add(&d.FailedTests, m[1])
+			} else if !strings.HasPrefix(line, "[ERROR]   ") && !strings.HasPrefix(line, "  ") {
+				inTestSummary = false
+			}
+		}
+
+		if m := parentPOMRe.FindStringSubmatch(line); m != nil {
+			d.ParentPOM = m[1]
+		}
+		for _, m := range missingArtifactRe.FindAllStringSubmatch(line, -1) {
+			add(&d.MissingArtifacts, strings.TrimSuffix(m[1], ","))
+		}
+		if m := unresolvedListRe.FindStringSubmatch(line); m != nil {
+			for _, c := range coordinatesRe.FindAllString(m[1], -1) {
+				add(&d.MissingArtifacts, c)
+			}
+		}
+
+		if m := enforcerRuleRe.FindStringSubmatch(line); m != nil {
+			rule := m[1][strings.LastIndex(m[1], ".")+1:]
+			msg := strings.TrimSpace(m[2])
+			if msg == "" && i+1 < len(lines) {
+				msg = strings.TrimSpace(logLevelRe.ReplaceAllString(lines[i+1], ""))
+			}
+			add(&d.EnforcerRules, strings.TrimSuffix(rule+": "+msg, ": "))
+		}
+	}
+	d.FailedTests = dedupeTestClasses(d.FailedTests)
+
+	d.Hints = hints(d, string(output))
+	if d.Module == "" && len(d.CompilerErrors) == 0 && len(d.FailedTests) == 0 &&
+		len(d.MissingArtifacts) == 0 && d.ParentPOM == "" && len(d.EnforcerRules) == 0 && len(d.Hints) == 0 {
+		return nil
+	}
+	return d
+}
+
+// compilerError parses a javac or kotlinc error line.
+func compilerError(line string) (CompilerError, bool) {
+	m := javacErrorRe.FindStringSubmatch(line)
+	if m == nil {
+		m = kotlincErrorRe.FindStringSubmatch(line)
+	}
+	if m == nil {
+		return CompilerError{}, false
+	}
+	lineNo, _ := strconv.Atoi(m[2])
+	col, _ := strconv.Atoi(m[3])
+	return CompilerError{File: m[1], Line: lineNo, Column: col, Message: strings.TrimSpace(m[4])}, true
+}
+
+// shortGoal drops the groupId from a plugin goal such as
+// org.apache.maven.plugins:maven-compiler-plugin:3.13.0:compile.
+func shortGoal(goal string) string {
+	if parts := strings.Split(goal, ":"); len(parts) == 4 {
+		return strings.Join(parts[1:], ":")
+	}
+	return goal
+}
+
+// dedupeTestClasses drops simple class names that are also listed fully
+// qualified: Surefire names the class in full in the per-class result line
+// but only by its simple name in the summary.
+func dedupeTestClasses(classes []string) []string {
+	qualified := make(map[string]bool)
+	for _, c := range classes {
+		if i := strings.LastIndex(c, "."); i >= 0 {
+			qualified[c[i+1:]] = true
+		}
+	}
+	var out []string
+	for _, c := range classes {
+		if strings.Contains(c, ".") || !qualified[c] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// FrameworkGroupID is the groupId of the Firefly Framework artifacts.
+const FrameworkGroupID = "org.fireflyframework"
+
+// hints returns remediation hints for the known failure signatures found in
+// d and the raw output.
+func hints(d *Diagnosis, output string) []string {
+	var out []string
+	has := func(subs ...string) bool {
+		for _, s := range subs {
+			if strings.Contains(output, s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	missingFramework := false
+	for _, a := range d.MissingArtifacts {
+		if strings.HasPrefix(a, FrameworkGroupID+":") && !strings.Contains(a, ":fireflyframework-parent:") {
+			missingFramework = true
+		}
+	}
+	switch {
+	case d.ParentPOM != "" || strings.Contains(strings.Join(d.MissingArtifacts, " "), ":fireflyframework-parent:"):
+		out = append(out, "parent POM not installed → run 'flywork setup', or 'flywork build --repo fireflyframework-parent'")
+	case missingFramework:
+		out = append(out, "framework dependency not installed in ~/.m2 → build it first with 'flywork build --up-to <repo>' or run 'flywork setup'")
+	case len(d.MissingArtifacts) > 0 && has("status code: 401", "401 Unauthorized", "Not authorized"):
+		out = append(out, "the repository rejected the credentials → check GITHUB_TOKEN and the <server> entries in ~/.m2/settings.xml")
+	case len(d.MissingArtifacts) > 0 && has("UnknownHostException", "Connection refused", "Network is unreachable", "timed out", "Could not transfer artifact"):
+		out = append(out, "network error while downloading dependencies → check connectivity and proxy settings, then retry")
+	case len(d.MissingArtifacts) > 0 && has("was cached in the local repository"):
+		out = append(out, "Maven cached an earlier failed download → retry once the artifact is published; the build runs with -U to refresh it")
+	case len(d.MissingArtifacts) > 0:
+		out = append(out, "artifact not found in any configured repository → check its version and the repositories/mirrors in ~/.m2/settings.xml")
+	}
+
+	if has("invalid target release", "invalid source release", "UnsupportedClassVersionError", "class file has wrong version") ||
+		releaseNotSupportedRe.MatchString(output) {
+		out = append(out, "the JDK is older than the project's Java release → select a newer JDK with --jdk or 'flywork config set java_version <version>'")
+	}
+
+	for _, rule := range d.EnforcerRules {
+		switch {
+		case strings.HasPrefix(rule, "RequireJavaVersion"):
+			out = append(out, "enforcer requires a different JDK → select one with --jdk or 'flywork config set java_version <version>'")
+		case strings.HasPrefix(rule, "RequireMavenVersion"):
+			out = append(out, "enforcer requires a newer Maven → upgrade Maven ('flywork doctor' shows the installed version)")
+		case strings.HasPrefix(rule, "DependencyConvergence"), strings.HasPrefix(rule, "RequireUpperBoundDeps"), strings.HasPrefix(rule, "BanDuplicatePomDependencyVersions"):
+			out = append(out, "conflicting dependency versions → align them in <dependencyManagement> or update the BOM")
+		case strings.HasPrefix(rule, "BannedDependencies"):
+			out = append(out, "a banned dependency is on the classpath → exclude it or replace it with the allowed alternative")
+		default:
+			out = append(out, "enforcer rule violated → fix the reported problem or check the project's maven-enforcer-plugin configuration")
+		}
+	}
+
+	for _, e := range d.CompilerErrors {
+		if strings.Contains(e.Message, "cannot find symbol") || strings.Contains(e.Message, "does not exist") ||
+			strings.Contains(e.Message, "Unresolved reference") {
+			out = append(out, "code refers to an API missing from the installed dependencies → rebuild them with 'flywork build --up-to <repo>'")
+			break
+		}
+	}
+	if len(d.FailedTests) > 0 {
+		out = append(out, "tests failed → see target/surefire-reports in the module; --skip-tests builds without running them")
+	}
+	if has("OutOfMemoryError", "Java heap space", "GC overhead limit exceeded") {
+		out = append(out, "Maven ran out of memory → raise --mem-per-job or lower --jobs")
+	}
+	if has("No space left on device") {
+		out = append(out, "disk full → free space, e.g. with 'flywork cache prune'")
+	}
+	if has("The following files had format violations") || strings.Contains(d.Goal, "spotless") {
+		out = append(out, "formatting violations → run 'mvn spotless:apply' in the repo")
+	}
+
+	// The same remediation can follow from several signatures.
+	var unique []string
+	seen := make(map[string]bool)
+	for _, h := range out {
+		if !seen[h] {
+			seen[h] = true
+			unique = append(unique, h)
+		}
+	}
+	return unique
+}
+
+// Summary returns a one-line description of the failure, or "" when the
+// diagnosis is nil.
+func (d *Diagnosis) Summary() string {
+	if d == nil {
+		return ""
+	}
+	in := ""
+	if d.Module != "" {
+		in = " in " + d.Module
+	}
+	switch {
+	case len(d.CompilerErrors) > 0:
+		return fmt.Sprintf("compilation failed%s: %s", in, plural(len(d.CompilerErrors), "error"))
+	case len(d.FailedTests) > 0:
+		return fmt.Sprintf("%s failed%s", plural(len(d.FailedTests), "test class", "test classes"), in)
+	case d.ParentPOM != "":
+		return fmt.Sprintf("parent POM %s not found", d.ParentPOM)
+	case len(d.MissingArtifacts) > 0:
+		return fmt.Sprintf("could not resolve %s%s", strings.Join(d.MissingArtifacts, ", "), in)
+	case len(d.EnforcerRules) > 0:
+		return fmt.Sprintf("enforcer rule %s failed%s", strings.SplitN(d.EnforcerRules[0], ":", 2)[0], in)
+	case d.Goal != "":
+		return fmt.Sprintf("%s failed%s", d.Goal, in)
+	case d.Module != "":
+		return "build failed" + in
+	}
+	return ""
+}
+
+// Details returns the individual findings of the diagnosis, at most limit
+// of each kind (0 for all), for display below its summary.
+func (d *Diagnosis) Details(limit int) []string {
+	if d == nil {
+		return nil
+	}
+	var out []string
+	list := func(items []string, prefix string) {
+		for i, item := range items {
+			if limit > 0 && i == limit {
+				out = append(out, fmt.Sprintf("… and %d more", len(items)-limit))
+				break
+			}
+			out = append(out, prefix+item)
+		}
+	}
+	errs := make([]string, len(d.CompilerErrors))
+	for i, e := range d.CompilerErrors {
+		errs[i] = e.String()
+	}
+	list(errs, "")
+	list(d.FailedTests, "Failed test: ")
+	list(d.MissingArtifacts, "Missing: ")
+	list(d.EnforcerRules, "Enforcer: ")
+	return out
+}
+
+// plural formats n with the singular or plural form of a noun; the plural
+// defaults to the singular with an "s" appended.
+func plural(n int, forms ...string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", forms[0])
+	}
+	if len(forms) > 1 {
+		return fmt.Sprintf("%d %s", n, forms[1])
+	}
+	return fmt.Sprintf("%d %ss", n, forms[0])
+}
@@ -0,0 +1,224 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	hintMissingAPI = "code refers to an API missing from the installed dependencies → rebuild them with 'flywork build --up-to <repo>'"
+	hintTests      = "tests failed → see target/surefire-reports in the module; --skip-tests builds without running them"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *Diagnosis
+	}{
+		{
+			name: "javac errors with symbol continuation",
+			output: `[INFO] --- maven-compiler-plugin:3.13.0:compile (default-compile) @ fireflyframework-web ---
+[INFO] Compiling 42 source files with javac [debug release 21] to target/classes
+[INFO] -------------------------------------------------------------
+[ERROR] COMPILATION ERROR : 
+[INFO] -------------------------------------------------------------
+[ERROR] /ws/fireflyframework-web/src/main/java/org/fireflyframework/web/Handler.java:[12,8] cannot find symbol
+  symbol:   class ReactiveContext
+  location: package org.fireflyframework.core
+[ERROR] /ws/fireflyframework-web/src/main/java/org/fireflyframework/web/Router.java:[30,15] incompatible types: java.lang.String cannot be converted to int
+[INFO] 2 errors 
+[INFO] -------------------------------------------------------------
+[INFO] BUILD FAILURE
+[ERROR] Failed to execute goal org.apache.maven.plugins:maven-compiler-plugin:3.13.0:compile (default-compile) on project fireflyframework-web: Compilation failure: Compilation failure: 
+[ERROR] /ws/fireflyframework-web/src/main/java/org/fireflyframework/web/Handler.java:[12,8] cannot find symbol
+[ERROR]   symbol:   class ReactiveContext
+[ERROR]   location: package org.fireflyframework.core
+[ERROR] /ws/fireflyframework-web/src/main/java/org/fireflyframework/web/Router.java:[30,15] incompatible types: java.lang.String cannot be converted to int
+[ERROR] -> [Help 1]
+`,
+			want: &Diagnosis{
+				Module:  "fireflyframework-web",
+				Goal:    "maven-compiler-plugin:3.13.0:compile",
+				Message: "Compilation failure: Compilation failure:",
+				CompilerErrors: []CompilerError{
+					{File: "/ws/fireflyframework-web/src/main/java/org/fireflyframework/web/Handler.java", Line: 12, Column: 8, Message: "cannot find symbol (symbol: class ReactiveContext)"},
+					{File: "/ws/fireflyframework-web/src/main/java/org/fireflyframework/web/Router.java", Line: 30, Column: 15, Message: "incompatible types: java.lang.String cannot be converted to int"},
+				},
+				Hints: []string{hintMissingAPI},
+			},
+		},
+		{
+			name: "kotlinc error",
+			output: `[INFO] --- kotlin-maven-plugin:2.1.0:compile (compile) @ fireflyframework-kotlin ---
+[ERROR] e: file:///ws/fireflyframework-kotlin/src/main/kotlin/org/fireflyframework/Dsl.kt:14:5 Unresolved reference: route
+[ERROR] Failed to execute goal org.jetbrains.kotlin:kotlin-maven-plugin:2.1.0:compile (compile) on project fireflyframework-kotlin: Compilation failure
+`,
+			want: &Diagnosis{
+				Module:  "fireflyframework-kotlin",
+				Goal:    "kotlin-maven-plugin:2.1.0:compile",
+				Message: "Compilation failure",
+				CompilerErrors: []CompilerError{
+					{File: "/ws/fireflyframework-kotlin/src/main/kotlin/org/fireflyframework/Dsl.kt", Line: 14, Column: 5, Message: "Unresolved reference: route"},
+				},
+				Hints: []string{hintMissingAPI},
+			},
+		},
+		{
+			name: "surefire failures and errors",
+			output: `[INFO] Running org.fireflyframework.core.ConfigTest
+[ERROR] Tests run: 3, Failures: 1, Errors: 0, Skipped: 0, Time elapsed: 0.215 s <<< FAILURE! -- in org.fireflyframework.core.ConfigTest
+[ERROR] org.fireflyframework.core.ConfigTest.loadsDefaults -- Time elapsed: 0.031 s <<< FAILURE!
+org.opentest4j.AssertionFailedError: expected: <1> but was: <2>
+	at org.fireflyframework.core.ConfigTest.loadsDefaults(ConfigTest.java:42)
+
+[INFO] Running org.fireflyframework.core.CacheTest
+[ERROR] Tests run: 2, Failures: 0, Errors: 1, Skipped: 0, Time elapsed: 0.104 s <<< ERROR! -- in org.fireflyframework.core.CacheTest
+[INFO] 
+[INFO] Results:
+[INFO] 
+[ERROR] Failures: 
+[ERROR]   ConfigTest.loadsDefaults:42 expected: <1> but was: <2>
+[ERROR] Errors: 
+[ERROR]   CacheTest.evicts:17 » IllegalState cache closed
+[ERROR] org.fireflyframework.core.SchedulerTest.runs
+[ERROR]   Run 1: SchedulerTest.runs:12 » Timeout
+[ERROR]   Run 2: SchedulerTest.runs:12 » Timeout
+[INFO] 
+[ERROR]   LaterTest.notInTheSummary:1
+[ERROR] Tests run: 6, Failures: 1, Errors: 2, Skipped: 0
+[INFO] 
+[ERROR] Failed to execute goal org.apache.maven.plugins:maven-surefire-plugin:3.2.5:test (default-test) on project fireflyframework-core: There are test failures.
+`,
+			want: &Diagnosis{
+				Module:      "fireflyframework-core",
+				Goal:        "maven-surefire-plugin:3.2.5:test",
+				Message:     "There are test failures.",
+				FailedTests: []string{"org.fireflyframework.core.ConfigTest", "org.fireflyframework.core.CacheTest", "org.fireflyframework.core.SchedulerTest"},
+				Hints:       []string{hintTests},
+			},
+		},
+		{
+			name:   "unresolved framework dependencies",
+			output: "[ERROR] Failed to execute goal on project fireflyframework-web: Could not resolve dependencies for project org.fireflyframework:fireflyframework-web:jar:1.0.0: The following artifacts could not be resolved: org.fireflyframework:fireflyframework-core:jar:1.0.0 (absent), org.fireflyframework:fireflyframework-utils:jar:1.0.0 (absent): Could not find artifact org.fireflyframework:fireflyframework-core:jar:1.0.0 in central (https://repo.maven.apache.org/maven2) -> [Help 1]\n",
+			want: &Diagnosis{
+				Module:           "fireflyframework-web",
+				Message:          "Could not resolve dependencies for project org.fireflyframework:fireflyframework-web:jar:1.0.0: The following artifacts could not be resolved: org.fireflyframework:fireflyframework-core:jar:1.0.0 (absent), org.fireflyframework:fireflyframework-utils:jar:1.0.0 (absent): Could not find artifact org.fireflyframework:fireflyframework-core:jar:1.0.0 in central (https://repo.maven.apache.org/maven2) -> [Help 1]",
+				MissingArtifacts: []string{"org.fireflyframework:fireflyframework-core:jar:1.0.0", "org.fireflyframework:fireflyframework-utils:jar:1.0.0"},
+				Hints:            []string{"framework dependency not installed in ~/.m2 → build it first with 'flywork build --up-to <repo>' or run 'flywork setup'"},
+			},
+		},
+		{
+			name:   "rejected credentials",
+			output: "[ERROR] Failed to execute goal on project acme-app: Could not resolve dependencies for project com.acme:acme-app:jar:1.0.0: Could not transfer artifact com.acme:acme-lib:jar:2.0.0 from/to github (https://maven.pkg.github.com/acme/*): status code: 401, reason phrase: Unauthorized (401) -> [Help 1]\n",
+			want: &Diagnosis{
+				Module:           "acme-app",
+				Message:          "Could not resolve dependencies for project com.acme:acme-app:jar:1.0.0: Could not transfer artifact com.acme:acme-lib:jar:2.0.0 from/to github (https://maven.pkg.github.com/acme/*): status code: 401, reason phrase: Unauthorized (401) -> [Help 1]",
+				MissingArtifacts: []string{"com.acme:acme-lib:jar:2.0.0"},
+				Hints:            []string{"the repository rejected the credentials → check GITHUB_TOKEN and the <server> entries in ~/.m2/settings.xml"},
+			},
+		},
+		{
+			name: "unresolvable parent POM",
+			output: `[ERROR] [ERROR] Some problems were encountered while processing the POMs:
+[FATAL] Non-resolvable parent POM for org.fireflyframework:fireflyframework-core:1.0.0: Could not find artifact org.fireflyframework:fireflyframework-parent:pom:1.0.0 in central (https://repo.maven.apache.org/maven2) and 'parent.relativePath' points at wrong local POM @ line 6, column 13
+[ERROR] The build could not read 1 project -> [Help 1]
+`,
+			want: &Diagnosis{
+				MissingArtifacts: []string{"org.fireflyframework:fireflyframework-parent:pom:1.0.0"},
+				ParentPOM:        "org.fireflyframework:fireflyframework-parent:pom:1.0.0",
+				Hints:            []string{"parent POM not installed → run 'flywork setup', or 'flywork build --repo fireflyframework-parent'"},
+			},
+		},
+		{
+			name: "enforcer rules",
+			output: `[INFO] --- maven-enforcer-plugin:3.5.0:enforce (enforce-versions) @ fireflyframework-parent ---
+[ERROR] Rule 0: org.apache.maven.enforcer.rules.version.RequireJavaVersion failed with message:
+[ERROR] Detected JDK version 17.0.12 (JAVA_HOME=/usr/lib/jvm/java-17) is not in the allowed range [21,).
+[ERROR] Rule 1: org.apache.maven.enforcer.rules.dependency.DependencyConvergence failed with message: Failed while enforcing releasability.
+[ERROR] Failed to execute goal org.apache.maven.plugins:maven-enforcer-plugin:3.5.0:enforce (enforce-versions) on project fireflyframework-parent: 
+`,
+			want: &Diagnosis{
+				Module: "fireflyframework-parent",
+				Goal:   "maven-enforcer-plugin:3.5.0:enforce",
+				EnforcerRules: []string{
+					"RequireJavaVersion: Detected JDK version 17.0.12 (JAVA_HOME=/usr/lib/jvm/java-17) is not in the allowed range [21,).",
+					"DependencyConvergence: Failed while enforcing releasability.",
+				},
+				Hints: []string{
+					"enforcer requires a different JDK → select one with --jdk or 'flywork config set java_version <version>'",
+					"conflicting dependency versions → align them in <dependencyManagement> or update the BOM",
+				},
+			},
+		},
+		{
+			name:   "JDK older than the release",
+			output: "[ERROR] Failed to execute goal org.apache.maven.plugins:maven-compiler-plugin:3.13.0:compile (default-compile) on project fireflyframework-core: Fatal error compiling: error: release version 25 not supported -> [Help 1]\n",
+			want: &Diagnosis{
+				Module:  "fireflyframework-core",
+				Goal:    "maven-compiler-plugin:3.13.0:compile",
+				Message: "Fatal error compiling: error: release version 25 not supported -> [Help 1]",
+				Hints:   []string{"the JDK is older than the project's Java release → select a newer JDK with --jdk or 'flywork config set java_version <version>'"},
+			},
+		},
+		{
+			name: "format violations",
+			output: `[ERROR] Failed to execute goal com.diffplug.spotless:spotless-maven-plugin:2.43.0:check (default) on project fireflyframework-utils: The following files had format violations:
+[ERROR]     src/main/java/org/fireflyframework/utils/Strings.java
+`,
+			want: &Diagnosis{
+				Module:  "fireflyframework-utils",
+				Goal:    "spotless-maven-plugin:2.43.0:check",
+				Message: "The following files had format violations:",
+				Hints:   []string{"formatting violations → run 'mvn spotless:apply' in the repo"},
+			},
+		},
+		{
+			name:   "out of memory without a failed goal",
+			output: "[ERROR] java.lang.OutOfMemoryError: Java heap space\r\n",
+			want: &Diagnosis{
+				Hints: []string{"Maven ran out of memory → raise --mem-per-job or lower --jobs"},
+			},
+		},
+		{
+			name:   "unrelated output",
+			output: "[INFO] Building fireflyframework-core 1.0.0\n[WARNING] Using platform encoding\n[INFO] BUILD SUCCESS\n",
+			want:   nil,
+		},
+		{
+			name:   "empty output",
+			output: "",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze([]byte(tt.output))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupeTestClasses(t *testing.T) {
+	got := dedupeTestClasses([]string{"com.acme.FooTest", "FooTest", "BarTest", "com.acme.other.BarTest", "BazTest"})
+	if want := []string{"com.acme.FooTest", "com.acme.other.BarTest", "BazTest"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupeTestClasses() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Artifact is a Maven module that a repository installs into the local
+// repository, with the checksum of the installed file.
+type Artifact struct {
+	GroupID    string `json:"group_id"`
+	ArtifactID string `json:"artifact_id"`
+	Version    string `json:"version"`
+	Packaging  string `json:"packaging,omitempty"`
+	SHA256     string `json:"sha256,omitempty"`
+}
+
+// GAV returns the artifact's groupId:artifactId:version coordinate.
+func (a Artifact) GAV() string {
+	return a.GroupID + ":" + a.ArtifactID + ":" + a.Version
+}
+
+// LocalRepository returns the local Maven repository (~/.m2/repository).
+func LocalRepository() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".m2", "repository")
+	}
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// Path returns the location of the artifact's main file in the local
+// repository: the .pom for pom-packaged modules, otherwise the jar (or war).
+func (a Artifact) Path() string {
+	ext := "jar"
+	switch a.Packaging {
+	case "pom", "war", "ear":
+		ext = a.Packaging
+	}
+	groupPath := strings.ReplaceAll(a.GroupID, ".", string(filepath.Separator))
+	return filepath.Join(LocalRepository(), groupPath, a.ArtifactID, a.Version,
+		a.ArtifactID+"-"+a.Version+"."+ext)
+}
+
+// Checksum returns the SHA-256 of the artifact's installed file.
+func (a Artifact) Checksum() (string, error) {
+	f, err := os.Open(a.Path())
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify checks that the artifact is still installed in the local repository
+// with the recorded checksum.
+func (a Artifact) Verify() error {
+	sum, err := a.Checksum()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s is missing from %s", a.GAV(), LocalRepository())
+		}
+		return fmt.Errorf("%s: %w", a.GAV(), err)
+	}
+	if a.SHA256 != "" && sum != a.SHA256 {
+		return fmt.Errorf("%s in %s differs from the last build", a.GAV(), LocalRepository())
+	}
+	return nil
+}
+
+// InstalledArtifacts returns the modules a repository installs, read from its
+// root pom.xml and the submodules it declares, with the checksums of the
+// files currently in the local repository. Modules whose version cannot be
+// resolved are left out; modules that are not installed have no checksum.
+func InstalledArtifacts(repoDir string) ([]Artifact, error) {
+	var out []Artifact
+	err := walkPoms(repoDir, func(_ string, p *artifactPom, props map[string]string) {
+		if a, ok := p.artifact(props); ok {
+			a.SHA256, _ = a.Checksum()
+			out = append(out, a)
+		}
+	})
+	return out, err
+}
+
+// ModuleDirs returns the directories of the submodules a repository's root
+// pom.xml declares, recursively and relative to repoDir with forward slashes
+// (e.g. "core", "modules/foo").
+func ModuleDirs(repoDir string) ([]string, error) {
+	var dirs []string
+	err := walkPoms(repoDir, func(dir string, _ *artifactPom, _ map[string]string) {
+		if rel, err := filepath.Rel(repoDir, dir); err == nil && rel != "." {
+			dirs = append(dirs, filepath.ToSlash(rel))
+		}
+	})
+	return dirs, err
+}
+
+// walkPoms calls fn for a repository's root pom.xml and, recursively, for
+// every submodule it declares, with the directory of the POM and the
+// properties in effect for it. Submodules whose pom cannot be read are left
+// out.
+func walkPoms(repoDir string, fn func(dir string, p *artifactPom, props map[string]string)) error {
+	root, err := readArtifactPom(filepath.Join(repoDir, "pom.xml"))
+	if err != nil {
+		return err
+	}
+
+	var walk func(dir string, p *artifactPom, inherited map[string]string)
+	walk = func(dir string, p *artifactPom, inherited map[string]string) {
+		props := p.properties(inherited)
+		fn(dir, p, props)
+		for _, mod := range p.Modules {
+			mod = strings.TrimSpace(mod)
+			modDir := filepath.Join(dir, mod)
+			pomPath := filepath.Join(modDir, "pom.xml")
+			if strings.HasSuffix(mod, ".xml") {
+				pomPath = modDir
+				modDir = filepath.Dir(modDir)
+			}
+			child, err := readArtifactPom(pomPath)
+			if err != nil {
+				continue
+			}
+			walk(modDir, child, props)
+		}
+	}
+	walk(repoDir, root, nil)
+	return nil
+}
+
+// artifactPom is the subset of a pom.xml needed to compute its coordinates.
+type artifactPom struct {
+	XMLName    xml.Name `xml:"project"`
+	GroupID    string   `xml:"groupId"`
+	ArtifactID string   `xml:"artifactId"`
+	Version    string   `xml:"version"`
+	Packaging  string   `xml:"packaging"`
+	Name       string   `xml:"name"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	Modules []string `xml:"modules>module"`
+}
+
+func readArtifactPom(path string) (*artifactPom, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p artifactPom
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// properties returns the POM's properties on top of those inherited from its
+// aggregator, which in framework repositories is also its parent.
+func (p *artifactPom) properties(inherited map[string]string) map[string]string {
+	props := make(map[string]string, len(inherited)+len(p.Properties.Entries))
+	for k, v := range inherited {
+		props[k] = v
+	}
+	for _, e := range p.Properties.Entries {
+		props[e.XMLName.Local] = strings.TrimSpace(e.Value)
+	}
+	return props
+}
+
+var placeholderRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// artifact returns the POM's coordinates, inheriting groupId and version from
+// the parent and expanding ${...} properties. ok is false when the
+// coordinates cannot be fully resolved.
+func (p *artifactPom) artifact(props map[string]string) (Artifact, bool) {
+	a := Artifact{
+		GroupID:    strings.TrimSpace(p.GroupID),
+		ArtifactID: strings.TrimSpace(p.ArtifactID),
+		Version:    strings.TrimSpace(p.Version),
+		Packaging:  strings.TrimSpace(p.Packaging),
+	}
+	if a.GroupID == "" {
+		a.GroupID = strings.TrimSpace(p.Parent.GroupID)
+	}
+	if a.Version == "" {
+		a.Version = strings.TrimSpace(p.Parent.Version)
+	}
+	if a.Packaging == "" {
+		a.Packaging = "jar"
+	}
+
+	expand := func(s string) string {
+		return placeholderRe.ReplaceAllStringFunc(s, func(m string) string {
+			key := m[2 : len(m)-1]
+			switch key {
+			case "project.version", "version":
+				return a.Version
+			case "project.groupId", "groupId":
+				return a.GroupID
+			case "project.parent.version", "parent.version":
+				return strings.TrimSpace(p.Parent.Version)
+			}
+			if v, ok := props[key]; ok {
+				return v
+			}
+			return m
+		})
+	}
+	a.GroupID = expand(a.GroupID)
+	a.Version = expand(a.Version)
+
+	if a.GroupID == "" || a.ArtifactID == "" || a.Version == "" ||
+		strings.Contains(a.GroupID+a.ArtifactID+a.Version, "${") {
+		return Artifact{}, false
+	}
+	return a, true
+}
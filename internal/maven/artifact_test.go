@@ -0,0 +1,55 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestModuleDirs(t *testing.T) {
+	dir := t.TempDir()
+	poms := map[string]string{
+		"pom.xml":                  `<project><modules><module>core</module><module>modules/foo</module><module>missing</module></modules></project>`,
+		"core/pom.xml":             `<project/>`,
+		"modules/foo/pom.xml":      `<project><modules><module>impl</module></modules></project>`,
+		"modules/foo/impl/pom.xml": `<project/>`,
+	}
+	for name, content := range poms {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := ModuleDirs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if want := []string{"core", "modules/foo", "modules/foo/impl"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ModuleDirs() = %v, want %v", got, want)
+	}
+
+	if _, err := ModuleDirs(t.TempDir()); err == nil {
+		t.Error("ModuleDirs succeeded without a root pom.xml")
+	}
+}
@@ -15,12 +15,17 @@
 package maven
 
 import (
-	"bytes"
+	"context"
+	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/proc"
 )
 
 // IsInstalled checks if mvn is available on PATH.
@@ -44,9 +49,9 @@ func Version() (string, error) {
 }
 
 // Install runs mvn clean install in the given directory.
-// If skipTests is true, -DskipTests is appended.
-func Install(dir string, skipTests bool) error {
-	cmd := exec.Command("mvn", buildInstallArgs(skipTests)...)
+// If skipTests is true, -DskipTests is appended. Any extraArgs follow.
+func Install(dir string, skipTests bool, extraArgs ...string) error {
+	cmd := exec.Command("mvn", buildInstallArgs(Settings{}, skipTests, extraArgs)...)
 	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -54,15 +59,15 @@ func Install(dir string, skipTests bool) error {
 }
 
 // InstallQuiet runs mvn clean install silently.
-func InstallQuiet(dir string, skipTests bool) error {
-	cmd := exec.Command("mvn", buildInstallArgs(skipTests)...)
+func InstallQuiet(dir string, skipTests bool, extraArgs ...string) error {
+	cmd := exec.Command("mvn", buildInstallArgs(Settings{}, skipTests, extraArgs)...)
 	cmd.Dir = dir
 	return cmd.Run()
 }
 
 // InstallWithJava runs mvn clean install with a specific JAVA_HOME.
-func InstallWithJava(dir, javaHome string, skipTests bool) error {
-	cmd := exec.Command("mvn", buildInstallArgs(skipTests)...)
+func InstallWithJava(dir, javaHome string, skipTests bool, extraArgs ...string) error {
+	cmd := exec.Command("mvn", buildInstallArgs(Settings{}, skipTests, extraArgs)...)
 	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -73,8 +78,8 @@ func InstallWithJava(dir, javaHome string, skipTests bool) error {
 }
 
 // InstallQuietWithJava runs mvn clean install silently with a specific JAVA_HOME.
-func InstallQuietWithJava(dir, javaHome string, skipTests bool) error {
-	cmd := exec.Command("mvn", buildInstallArgs(skipTests)...)
+func InstallQuietWithJava(dir, javaHome string, skipTests bool, extraArgs ...string) error {
+	cmd := exec.Command("mvn", buildInstallArgs(Settings{}, skipTests, extraArgs)...)
 	cmd.Dir = dir
 	if javaHome != "" {
 		cmd.Env = appendJavaHome(os.Environ(), javaHome)
@@ -83,54 +88,161 @@ func InstallQuietWithJava(dir, javaHome string, skipTests bool) error {
 }
 
 // InstallQuietWithJavaOutput runs mvn clean install silently with a specific JAVA_HOME
-// and returns the combined stdout+stderr output along with any error.
-func InstallQuietWithJavaOutput(dir, javaHome string, skipTests bool) ([]byte, error) {
-	cmd := exec.Command("mvn", buildInstallArgs(skipTests)...)
-	cmd.Dir = dir
-	if javaHome != "" {
-		cmd.Env = appendJavaHome(os.Environ(), javaHome)
-	}
-	var buf bytes.Buffer
-	cmd.Stdout = &buf
-	cmd.Stderr = &buf
-	err := cmd.Run()
-	return buf.Bytes(), err
+// and returns the last TailLines lines of the combined stdout+stderr output
+// along with any error.
+func InstallQuietWithJavaOutput(dir, javaHome string, skipTests bool, extraArgs ...string) ([]byte, error) {
+	return InstallWithOptions(context.Background(), dir, RunOptions{JavaHome: javaHome}, skipTests, extraArgs...)
+}
+
+// InstallQuietOutput runs mvn clean install silently and returns the last
+// TailLines lines of the combined stdout+stderr output along with any error.
+func InstallQuietOutput(dir string, skipTests bool, extraArgs ...string) ([]byte, error) {
+	return InstallWithOptions(context.Background(), dir, RunOptions{}, skipTests, extraArgs...)
 }
 
-// InstallQuietOutput runs mvn clean install silently and returns the combined
-// stdout+stderr output along with any error.
-func InstallQuietOutput(dir string, skipTests bool) ([]byte, error) {
-	cmd := exec.Command("mvn", buildInstallArgs(skipTests)...)
+// InstallWithOptions runs mvn clean install silently — or the goals of
+// opts.Maven — with the JAVA_HOME, heap budget and Maven settings of opts,
+// streams the combined stdout+stderr output to the log file and tail func of
+// opts, and returns its last TailLines lines along with any error. Cancelling
+// ctx terminates Maven and the processes it started.
+func InstallWithOptions(ctx context.Context, dir string, opts RunOptions, skipTests bool, extraArgs ...string) ([]byte, error) {
+	cmd := command(ctx, buildInstallArgs(opts.Maven, skipTests, extraArgs)...)
 	cmd.Dir = dir
-	var buf bytes.Buffer
-	cmd.Stdout = &buf
-	cmd.Stderr = &buf
-	err := cmd.Run()
-	return buf.Bytes(), err
+	cmd.Env = opts.env()
+	return opts.run(cmd)
 }
 
-// buildInstallArgs returns the Maven arguments for clean install.
-func buildInstallArgs(skipTests bool) []string {
-	args := []string{"clean", "install", "-q", "-U"}
+// buildInstallArgs returns the Maven arguments for an install build:
+// "clean install -q -U" unless s configures other goals or offline mode,
+// followed by the profiles, properties and arguments of s.
+func buildInstallArgs(s Settings, skipTests bool, extraArgs []string) []string {
+	args := append(slices.Clone(s.goals()), "-q")
+	args = append(args, s.updateFlags(extraArgs)...)
 	if skipTests {
 		args = append(args, "-DskipTests")
 	}
-	return args
+	args = append(args, s.flags()...)
+	return append(args, extraArgs...)
+}
+
+// RunOptions configures the environment and settings of a Maven invocation
+// and where its output goes.
+type RunOptions struct {
+	JavaHome string            // JAVA_HOME for the build; empty inherits the environment
+	HeapMB   int               // -Xmx budget added to MAVEN_OPTS without one; 0 keeps Maven's default
+	HeapSet  bool              // HeapMB was given explicitly (--mem-per-job) and replaces any -Xmx in MAVEN_OPTS
+	Maven    Settings          // Goals, profiles, properties, arguments and environment variables
+	LogFile  string            // File the output is streamed to, rotated at MaxLogSize; empty keeps no log
+	Tail     func(line string) // Receives each output line as Maven prints it; nil ignores them
+}
+
+// command returns an mvn command whose process group is terminated when ctx
+// is cancelled.
+func command(ctx context.Context, args ...string) *exec.Cmd {
+	return proc.Command(ctx, "mvn", args...)
+}
+
+// env returns the environment for a Maven process, or nil to inherit the
+// current one unchanged. The heap budget is added to MAVEN_OPTS, as set in
+// the Maven settings or inherited, unless it already sizes the heap with
+// -Xmx; an explicit budget (HeapSet) replaces that heap size instead.
+func (o RunOptions) env() []string {
+	if o.JavaHome == "" && o.HeapMB <= 0 && len(o.Maven.Env) == 0 {
+		return nil
+	}
+	env := os.Environ()
+	if o.JavaHome != "" {
+		env = appendJavaHome(env, o.JavaHome)
+	}
+	for _, key := range slices.Sorted(maps.Keys(o.Maven.Env)) {
+		env = setEnv(env, key, o.Maven.Env[key])
+	}
+	if o.HeapMB > 0 {
+		env = appendHeapBudget(env, o.HeapMB, o.HeapSet)
+	}
+	return env
+}
+
+// commandLine renders cmd as a shell command line, preceded by the
+// environment variables the options set (JAVA_HOME, MAVEN_OPTS and those of
+// the Maven settings).
+func (o RunOptions) commandLine(cmd *exec.Cmd) string {
+	keys := slices.Sorted(maps.Keys(o.Maven.Env))
+	if _, ok := o.Maven.Env["MAVEN_OPTS"]; o.HeapMB > 0 && !ok {
+		keys = append(keys, "MAVEN_OPTS")
+	}
+	if o.JavaHome != "" {
+		keys = append([]string{"JAVA_HOME"}, keys...)
+	}
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, key+"="+shellQuote(lookupEnv(cmd.Env, key)))
+	}
+	for _, arg := range cmd.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// lookupEnv returns the value of key in env, the last one winning.
+func lookupEnv(env []string, key string) string {
+	value := ""
+	for _, e := range env {
+		if v, ok := strings.CutPrefix(e, key+"="); ok {
+			value = v
+		}
+	}
+	return value
+}
+
+// shellQuote quotes s for display in a shell command line when needed.
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`*?;&|<>()") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func appendJavaHome(env []string, javaHome string) []string {
+	return setEnv(env, "JAVA_HOME", javaHome)
+}
+
+// setEnv sets key in env, replacing any value it already has.
+func setEnv(env []string, key, value string) []string {
 	filtered := make([]string, 0, len(env)+1)
 	for _, e := range env {
-		if !strings.HasPrefix(e, "JAVA_HOME=") {
+		if !strings.HasPrefix(e, key+"=") {
 			filtered = append(filtered, e)
 		}
 	}
-	return append(filtered, "JAVA_HOME="+javaHome)
+	return append(filtered, key+"="+value)
+}
+
+// appendHeapBudget sets -Xmx in MAVEN_OPTS, keeping the other options. A
+// heap size already present is kept unless replace is set.
+func appendHeapBudget(env []string, heapMB int, replace bool) []string {
+	var opts []string
+	filtered := make([]string, 0, len(env)+1)
+	for _, e := range env {
+		if v, ok := strings.CutPrefix(e, "MAVEN_OPTS="); ok {
+			for _, opt := range strings.Fields(v) {
+				if !strings.HasPrefix(opt, "-Xmx") {
+					opts = append(opts, opt)
+				} else if !replace {
+					return env
+				}
+			}
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	opts = append(opts, fmt.Sprintf("-Xmx%dm", heapMB))
+	return append(filtered, "MAVEN_OPTS="+strings.Join(opts, " "))
 }
 
 // Deploy runs mvn deploy with a GitHub Packages target repository.
-func Deploy(dir, javaHome string, skipTests bool, deployRepo string) error {
-	args := buildDeployArgs(skipTests, deployRepo)
+func Deploy(dir, javaHome string, skipTests bool, deployRepo string, extraArgs ...string) error {
+	args := buildDeployArgs(Settings{}, skipTests, deployRepo, extraArgs)
 	cmd := exec.Command("mvn", args...)
 	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
@@ -141,31 +253,36 @@ func Deploy(dir, javaHome string, skipTests bool, deployRepo string) error {
 	return cmd.Run()
 }
 
-// DeployQuietOutput runs mvn deploy silently and captures output.
-func DeployQuietOutput(dir, javaHome string, skipTests bool, deployRepo string) ([]byte, error) {
-	args := buildDeployArgs(skipTests, deployRepo)
-	cmd := exec.Command("mvn", args...)
+// DeployQuietOutput runs mvn deploy silently and returns the last TailLines
+// lines of its output.
+func DeployQuietOutput(dir, javaHome string, skipTests bool, deployRepo string, extraArgs ...string) ([]byte, error) {
+	return DeployWithOptions(context.Background(), dir, RunOptions{JavaHome: javaHome}, skipTests, deployRepo, extraArgs...)
+}
+
+// DeployWithOptions runs mvn deploy silently with the JAVA_HOME, heap budget
+// and Maven settings of opts (other than their goals), streams its output
+// like InstallWithOptions and returns the last TailLines lines. Cancelling
+// ctx terminates Maven and the processes it started.
+func DeployWithOptions(ctx context.Context, dir string, opts RunOptions, skipTests bool, deployRepo string, extraArgs ...string) ([]byte, error) {
+	cmd := command(ctx, buildDeployArgs(opts.Maven, skipTests, deployRepo, extraArgs)...)
 	cmd.Dir = dir
-	if javaHome != "" {
-		cmd.Env = appendJavaHome(os.Environ(), javaHome)
-	}
-	var buf bytes.Buffer
-	cmd.Stdout = &buf
-	cmd.Stderr = &buf
-	err := cmd.Run()
-	return buf.Bytes(), err
+	cmd.Env = opts.env()
+	return opts.run(cmd)
 }
 
-// buildDeployArgs returns the Maven arguments for deploy.
-func buildDeployArgs(skipTests bool, deployRepo string) []string {
-	args := []string{"-B", "clean", "deploy", "-P", "release"}
+// buildDeployArgs returns the Maven arguments for deploy: the release
+// profile is activated together with the profiles of s. Deploys always run
+// online.
+func buildDeployArgs(s Settings, skipTests bool, deployRepo string, extraArgs []string) []string {
+	args := []string{"-B", "clean", "deploy"}
 	if skipTests {
 		args = append(args, "-DskipTests")
 	}
 	if deployRepo != "" {
 		args = append(args, "-DaltDeploymentRepository="+deployRepo)
 	}
-	return args
+	args = append(args, s.flags("release")...)
+	return append(args, extraArgs...)
 }
 
 // ArtifactExistsInM2 checks if a given artifact exists in the local .m2 repository.
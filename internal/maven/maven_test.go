@@ -0,0 +1,57 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import "testing"
+
+func TestRunOptionsEnvHeap(t *testing.T) {
+	tests := []struct {
+		name  string
+		shell string
+		opts  RunOptions
+		want  string
+	}{
+		{"budget added", "", RunOptions{HeapMB: 1024}, "-Xmx1024m"},
+		{"budget appended to shell options", "-XX:+UseG1GC", RunOptions{HeapMB: 1024}, "-XX:+UseG1GC -Xmx1024m"},
+		{"shell heap kept", "-Xmx3g -XX:+UseG1GC", RunOptions{HeapMB: 1024}, "-Xmx3g -XX:+UseG1GC"},
+		{"shell heap replaced by --mem-per-job", "-Xmx3g -XX:+UseG1GC", RunOptions{HeapMB: 1024, HeapSet: true}, "-XX:+UseG1GC -Xmx1024m"},
+		{
+			name:  "configured heap kept",
+			shell: "-Xmx3g",
+			opts:  RunOptions{HeapMB: 1024, Maven: Settings{Env: map[string]string{"MAVEN_OPTS": "-Xmx2g"}}},
+			want:  "-Xmx2g",
+		},
+		{
+			name: "configured heap replaced by --mem-per-job",
+			opts: RunOptions{HeapMB: 1024, HeapSet: true, Maven: Settings{Env: map[string]string{"MAVEN_OPTS": "-Xmx2g -Dx=1"}}},
+			want: "-Dx=1 -Xmx1024m",
+		},
+		{
+			name:  "configured options without heap",
+			shell: "-Xmx3g",
+			opts:  RunOptions{HeapMB: 1024, Maven: Settings{Env: map[string]string{"MAVEN_OPTS": "-Dx=1"}}},
+			want:  "-Dx=1 -Xmx1024m",
+		},
+		{"no budget", "-Xmx3g", RunOptions{JavaHome: "/jdk"}, "-Xmx3g"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAVEN_OPTS", tt.shell)
+			if got := lookupEnv(tt.opts.env(), "MAVEN_OPTS"); got != tt.want {
+				t.Errorf("MAVEN_OPTS = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
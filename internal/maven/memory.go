@@ -0,0 +1,78 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	minHeapMB = 512
+	maxHeapMB = 4096
+)
+
+// HeapPerJobMB returns the -Xmx budget for each of jobs concurrent Maven
+// builds: half of the physical memory split evenly, leaving the other half
+// for forked test JVMs and the OS, clamped to 512 MB–4 GB. It returns 0 (keep
+// Maven's default) for a single job or when the memory size is unknown.
+func HeapPerJobMB(jobs int) int {
+	if jobs < 2 {
+		return 0
+	}
+	total := totalMemoryMB()
+	if total <= 0 {
+		return 0
+	}
+	return max(minHeapMB, min(maxHeapMB, total/2/jobs))
+}
+
+// totalMemoryMB returns the physical memory in MB, or 0 if unknown.
+func totalMemoryMB() int {
+	switch runtime.GOOS {
+	case "linux":
+		f, err := os.Open("/proc/meminfo")
+		if err != nil {
+			return 0
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "MemTotal:" {
+				kb, err := strconv.Atoi(fields[1])
+				if err != nil {
+					return 0
+				}
+				return kb / 1024
+			}
+		}
+	case "darwin":
+		out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+		if err != nil {
+			return 0
+		}
+		bytes, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err != nil {
+			return 0
+		}
+		return int(bytes / 1024 / 1024)
+	}
+	return 0
+}
@@ -0,0 +1,240 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Limits on the output of a Maven invocation: it is streamed to the log
+// file as it is written, and only its last lines are kept in memory.
+const (
+	TailLines    = 2000     // Output lines kept in memory for failure analysis
+	MaxLogSize   = 64 << 20 // Size at which a log file is rotated
+	LogBackups   = 2        // Rotated files kept next to the log (<log>.1, <log>.2)
+	maxLineBytes = 64 << 10 // Longer lines are split
+)
+
+// run runs cmd with its combined stdout and stderr streamed to the log file
+// and tail func of o, and returns the last TailLines lines of the output
+// along with any error. Both start with the effective command line.
+func (o RunOptions) run(cmd *exec.Cmd) ([]byte, error) {
+	out := newOutputSink(o.LogFile, o.Tail, TailLines)
+	out.header(o.commandLine(cmd), cmd.Dir)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	err := cmd.Run()
+	out.Close()
+	return out.Bytes(), err
+}
+
+// outputSink is the io.Writer Maven writes to. It appends the output to a
+// rotating log file, keeps its last lines in a ring buffer and passes each
+// complete line to a tail func. Failing to write the log file never fails
+// the build: the output is then only kept in memory.
+type outputSink struct {
+	mu      sync.Mutex
+	log     *rotatingFile
+	tail    func(line string)
+	lines   []string // Ring buffer of the last lines
+	next    int      // Index of the next line to overwrite in lines
+	full    bool     // lines has wrapped around
+	partial []byte   // Output after the last newline
+}
+
+func newOutputSink(logFile string, tail func(string), keep int) *outputSink {
+	s := &outputSink{tail: tail, lines: make([]string, max(keep, 1))}
+	if logFile != "" {
+		if f, err := openRotating(logFile, MaxLogSize, LogBackups); err == nil {
+			s.log = f
+		}
+	}
+	return s
+}
+
+// header starts the log file with the command line and its directory, and
+// passes the command line to the tail func.
+func (s *outputSink) header(cmdline, dir string) {
+	if s.tail != nil {
+		s.tail("$ " + cmdline)
+	}
+	if s.log != nil {
+		s.writeLog([]byte(fmt.Sprintf("=== %s ===\n=== in %s ===\n\n", cmdline, dir)))
+	}
+}
+
+func (s *outputSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeLog(p)
+	s.partial = append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 && len(s.partial) < maxLineBytes {
+			break
+		}
+		if i < 0 || i > maxLineBytes {
+			// Split lines longer than maxLineBytes, terminated or not.
+			s.addLine(s.partial[:maxLineBytes])
+			s.partial = s.partial[maxLineBytes:]
+			continue
+		}
+		s.addLine(s.partial[:i])
+		s.partial = s.partial[i+1:]
+	}
+	// Reuse the buffer instead of letting it grow with the consumed prefix.
+	s.partial = append([]byte(nil), s.partial...)
+	return len(p), nil
+}
+
+// Close flushes the last, unterminated line and closes the log file.
+func (s *outputSink) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.partial) > 0 {
+		s.addLine(s.partial)
+		s.partial = nil
+	}
+	if s.log != nil {
+		_ = s.log.Close()
+		s.log = nil
+	}
+}
+
+// Bytes returns the lines kept in memory, oldest first.
+func (s *outputSink) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var buf bytes.Buffer
+	if s.full {
+		for _, line := range s.lines[s.next:] {
+			buf.WriteString(line)
+			buf.WriteByte('\n')
+		}
+	}
+	for _, line := range s.lines[:s.next] {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func (s *outputSink) writeLog(p []byte) {
+	if s.log == nil {
+		return
+	}
+	if _, err := s.log.Write(p); err != nil {
+		_ = s.log.Close()
+		s.log = nil
+	}
+}
+
+// addLine stores a line in the ring buffer and passes it to the tail func;
+// the caller holds s.mu.
+func (s *outputSink) addLine(b []byte) {
+	line := strings.TrimRight(string(b), "\r")
+	s.lines[s.next] = line
+	s.next++
+	if s.next == len(s.lines) {
+		s.next, s.full = 0, true
+	}
+	if s.tail != nil {
+		s.tail(line)
+	}
+}
+
+// rotatingFile is a log file that is renamed to <path>.1 once it reaches
+// its maximum size, shifting older rotations up to <path>.<backups>.
+type rotatingFile struct {
+	path    string
+	max     int64
+	backups int
+	f       *os.File
+	size    int64
+}
+
+// openRotating creates or truncates the log file at path and removes the
+// rotations of an earlier log at the same path.
+func openRotating(path string, max int64, backups int) (*rotatingFile, error) {
+	for _, old := range LogSegments(path) {
+		if old != path {
+			_ = os.Remove(old)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &rotatingFile{path: path, max: max, backups: backups, f: f}, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.size > 0 && r.size+int64(len(p)) > r.max {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	if r.backups > 0 {
+		_ = os.Remove(segment(r.path, r.backups))
+		for i := r.backups - 1; i >= 0; i-- {
+			_ = os.Rename(segment(r.path, i), segment(r.path, i+1))
+		}
+	}
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	r.f, r.size = f, 0
+	return nil
+}
+
+func (r *rotatingFile) Close() error {
+	return r.f.Close()
+}
+
+// segment returns the name of the i-th rotation of the log at path; the
+// 0th is the log itself.
+func segment(path string, i int) string {
+	if i == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// LogSegments returns the existing files of the log at path, oldest
+// rotation first and the log itself last.
+func LogSegments(path string) []string {
+	var files []string
+	for i := LogBackups; i >= 0; i-- {
+		if _, err := os.Stat(segment(path, i)); err == nil {
+			files = append(files, segment(path, i))
+		}
+	}
+	return files
+}
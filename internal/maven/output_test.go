@@ -0,0 +1,188 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputSinkRingBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		keep   int
+		writes []string
+		want   string
+	}{
+		{"empty", 3, nil, ""},
+		{"not full", 3, []string{"1\n2\n"}, "1\n2\n"},
+		{"exactly full", 3, []string{"1\n2\n3\n"}, "1\n2\n3\n"},
+		{"wrapped", 3, []string{"1\n2\n3\n4\n5\n"}, "3\n4\n5\n"},
+		{"wrapped twice", 2, []string{"1\n2\n3\n4\n5\n"}, "4\n5\n"},
+		{"lines split across writes", 3, []string{"ab", "c\nd", "e\n"}, "abc\nde\n"},
+		{"unterminated last line", 3, []string{"a\nb"}, "a\nb\n"},
+		{"carriage returns trimmed", 3, []string{"a\r\nb\r\n"}, "a\nb\n"},
+		{"keep below one", 0, []string{"a\nb\n"}, "b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newOutputSink("", nil, tt.keep)
+			for _, w := range tt.writes {
+				if n, err := s.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			s.Close()
+			if got := string(s.Bytes()); got != tt.want {
+				t.Errorf("Bytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputSinkSplitsLongLines(t *testing.T) {
+	var lines []string
+	s := newOutputSink("", func(line string) { lines = append(lines, line) }, 10)
+
+	long := strings.Repeat("x", 2*maxLineBytes+10)
+	_, _ = s.Write([]byte(long[:100]))
+	_, _ = s.Write([]byte(long[100:] + "\nend\n"))
+	s.Close()
+
+	want := []string{long[:maxLineBytes], long[maxLineBytes : 2*maxLineBytes], long[2*maxLineBytes:], "end"}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(want))
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d has %d bytes, want %d", i, len(lines[i]), len(want[i]))
+		}
+	}
+	if len(s.partial) != 0 {
+		t.Errorf("%d bytes left unflushed", len(s.partial))
+	}
+}
+
+func TestOutputSinkTailAndLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+	var lines []string
+	s := newOutputSink(path, func(line string) { lines = append(lines, line) }, 10)
+	s.header("mvn clean install", "/ws/core")
+	_, _ = s.Write([]byte("[INFO] one\n[INFO] two"))
+	s.Close()
+
+	if want := []string{"$ mvn clean install", "[INFO] one", "[INFO] two"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("tail got %q, want %q", lines, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "=== mvn clean install ===\n=== in /ws/core ===\n\n[INFO] one\n[INFO] two"
+	if string(data) != want {
+		t.Errorf("log = %q, want %q", data, want)
+	}
+	// The header goes to the log and the tail, not to the buffer analysed
+	// on failure.
+	if got := string(s.Bytes()); got != "[INFO] one\n[INFO] two\n" {
+		t.Errorf("Bytes() = %q", got)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+	r, err := openRotating(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range []string{"aaaaaaaa", "bbbbbbbb", "cccccccc", "dddddddd"} {
+		if _, err := r.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Four chunks that each fill a file: the oldest is dropped beyond two
+	// backups, and the segments are listed oldest first.
+	segments := LogSegments(path)
+	if want := []string{path + ".2", path + ".1", path}; !reflect.DeepEqual(segments, want) {
+		t.Fatalf("LogSegments() = %v, want %v", segments, want)
+	}
+	for i, want := range []string{"bbbbbbbb", "cccccccc", "dddddddd"} {
+		data, err := os.ReadFile(segments[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", segments[i], data, want)
+		}
+	}
+
+	// Reopening the log starts over and removes the old rotations.
+	r, err = openRotating(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Close()
+	if got := LogSegments(path); !reflect.DeepEqual(got, []string{path}) {
+		t.Errorf("after reopening, LogSegments() = %v", got)
+	}
+}
+
+func TestRotatingFileOversizedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+	r, err := openRotating(path, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A write larger than the limit goes to an empty file as a whole
+	// instead of rotating it away.
+	_, _ = r.Write([]byte("0123456789"))
+	_, _ = r.Write([]byte("ab"))
+	_ = r.Close()
+
+	if want := []string{path + ".1", path}; !reflect.DeepEqual(LogSegments(path), want) {
+		t.Fatalf("LogSegments() = %v, want %v", LogSegments(path), want)
+	}
+	if data, _ := os.ReadFile(path + ".1"); string(data) != "0123456789" {
+		t.Errorf("rotated log = %q", data)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "ab" {
+		t.Errorf("log = %q", data)
+	}
+}
+
+func TestRotatingFileWithoutBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+	r, err := openRotating(path, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = r.Write([]byte("abcd"))
+	_, _ = r.Write([]byte("efgh"))
+	_ = r.Close()
+
+	if got := LogSegments(path); !reflect.DeepEqual(got, []string{path}) {
+		t.Errorf("LogSegments() = %v", got)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "efgh" {
+		t.Errorf("log = %q, want only the output after the rotation", data)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reactor module statuses as printed in Maven's reactor summary.
+const (
+	ModuleSuccess = "SUCCESS"
+	ModuleFailure = "FAILURE"
+	ModuleSkipped = "SKIPPED"
+)
+
+// ModuleResult is one line of a Maven reactor summary.
+type ModuleResult struct {
+	Name     string // Module name as displayed, possibly followed by its version
+	Status   string // ModuleSuccess, ModuleFailure or ModuleSkipped
+	Duration time.Duration
+}
+
+// aggregatorPom is a pom-packaged project that only lists modules.
+type aggregatorPom struct {
+	XMLName      xml.Name `xml:"project"`
+	ModelVersion string   `xml:"modelVersion"`
+	GroupID      string   `xml:"groupId"`
+	ArtifactID   string   `xml:"artifactId"`
+	Version      string   `xml:"version"`
+	Packaging    string   `xml:"packaging"`
+	Name         string   `xml:"name"`
+	Modules      []string `xml:"modules>module"`
+}
+
+// WriteAggregatorPOM writes a pom.xml to dir that aggregates the given module
+// directories, which are stored relative to dir, and returns its path.
+func WriteAggregatorPOM(dir string, moduleDirs []string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	pom := aggregatorPom{
+		ModelVersion: "4.0.0",
+		GroupID:      "org.fireflyframework.flywork",
+		ArtifactID:   "flywork-reactor",
+		Version:      "0",
+		Packaging:    "pom",
+		Name:         "flywork-reactor",
+	}
+	for _, m := range moduleDirs {
+		rel, err := filepath.Rel(dir, m)
+		if err != nil {
+			return "", err
+		}
+		pom.Modules = append(pom.Modules, filepath.ToSlash(rel))
+	}
+	data, err := xml.MarshalIndent(pom, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "pom.xml")
+	content := append([]byte(xml.Header+"<!-- Generated by flywork build --reactor; do not edit. -->\n"), data...)
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ReactorInstall runs a single multi-threaded mvn clean install — or the
+// goals of opts.Maven — over the aggregator pom at pomPath with the
+// JAVA_HOME, heap, Maven settings and output destinations of opts, and
+// returns the last TailLines lines of the combined stdout+stderr output
+// along with any error. The build runs in batch mode without -q so that the
+// reactor summary is printed. Cancelling ctx terminates Maven and the
+// processes it started.
+func ReactorInstall(ctx context.Context, pomPath string, opts RunOptions, threads int, skipTests bool, extraArgs ...string) ([]byte, error) {
+	if threads < 1 {
+		threads = 1
+	}
+	args := append([]string{"-B", "-f", pomPath, "-T", strconv.Itoa(threads)}, opts.Maven.goals()...)
+	args = append(args, opts.Maven.updateFlags(extraArgs)...)
+	if skipTests {
+		args = append(args, "-DskipTests")
+	}
+	args = append(args, opts.Maven.flags()...)
+	args = append(args, extraArgs...)
+
+	cmd := command(ctx, args...)
+	cmd.Dir = filepath.Dir(pomPath)
+	cmd.Env = opts.env()
+	return opts.run(cmd)
+}
+
+// reactorLineRe matches a reactor summary line such as
+// "[INFO] Firefly Kernel 1.0.0 ........ SUCCESS [  2.345 s]".
+var reactorLineRe = regexp.MustCompile(`^\[INFO\] (.+?) \.+ (SUCCESS|FAILURE|SKIPPED)(?: \[\s*([^\]]+)\])?\s*$`)
+
+// ParseReactorSummary extracts the module results from the "Reactor Summary"
+// section of Maven output, in reactor order. It returns nil if the output
+// has no reactor summary.
+func ParseReactorSummary(output []byte) []ModuleResult {
+	var results []ModuleResult
+	inSummary := false
+	sc := bufio.NewScanner(bytes.NewReader(output))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.Contains(line, "Reactor Summary") {
+			inSummary = true
+			results = nil
+			continue
+		}
+		if !inSummary {
+			continue
+		}
+		if strings.HasPrefix(line, "[INFO] BUILD ") {
+			inSummary = false
+			continue
+		}
+		m := reactorLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		results = append(results, ModuleResult{
+			Name:     strings.TrimSpace(m[1]),
+			Status:   m[2],
+			Duration: parseReactorDuration(m[3]),
+		})
+	}
+	return results
+}
+
+// parseReactorDuration parses the durations Maven prints in the reactor
+// summary: "2.345 s", "01:05 min" and "01:02 h".
+func parseReactorDuration(s string) time.Duration {
+	value, unit, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return 0
+	}
+	if unit == "s" {
+		secs, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0
+		}
+		return time.Duration(secs * float64(time.Second))
+	}
+
+	base := time.Minute
+	if unit == "h" {
+		base = time.Hour
+	}
+	var d time.Duration
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		d += time.Duration(n * float64(base))
+		base /= 60
+	}
+	return d
+}
+
+// ModuleNames returns the names under which a repository's modules appear in
+// a reactor summary: each module's <name>, or its artifactId when it has
+// none, both bare and followed by the module's version.
+func ModuleNames(repoDir string) ([]string, error) {
+	var names []string
+	err := walkPoms(repoDir, func(_ string, p *artifactPom, props map[string]string) {
+		a, ok := p.artifact(props)
+		if !ok {
+			a = Artifact{ArtifactID: strings.TrimSpace(p.ArtifactID)}
+		}
+		name := strings.TrimSpace(p.Name)
+		name = strings.NewReplacer("${project.artifactId}", a.ArtifactID, "${artifactId}", a.ArtifactID).Replace(name)
+		if name == "" {
+			name = a.ArtifactID
+		}
+		if name == "" {
+			return
+		}
+		names = append(names, name)
+		if a.Version != "" {
+			names = append(names, fmt.Sprintf("%s %s", name, a.Version))
+		}
+	})
+	return names, err
+}
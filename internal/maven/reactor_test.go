@@ -0,0 +1,115 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseReactorSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []ModuleResult
+	}{
+		{
+			name:   "no summary",
+			output: "[INFO] Building kernel 1.0.0\n[INFO] BUILD SUCCESS\n",
+			want:   nil,
+		},
+		{
+			name: "success, failure and skipped modules",
+			output: `[INFO] Compiling 12 source files
+[INFO] ------------------------------------------------------------------------
+[INFO] Reactor Summary for flywork-reactor 0:
+[INFO]
+[INFO] Firefly Kernel 1.0.0 ............................... SUCCESS [  2.345 s]
+[INFO] fireflyframework-utils 1.0.0 ....................... FAILURE [01:05 min]
+[INFO] Firefly Web 1.0.0 .................................. SKIPPED
+[INFO] flywork-reactor 0 .................................. SUCCESS [  0.001 s]
+[INFO] ------------------------------------------------------------------------
+[INFO] BUILD FAILURE
+[INFO] Firefly Ignored 1.0.0 .............................. SUCCESS [  1.000 s]
+`,
+			want: []ModuleResult{
+				{Name: "Firefly Kernel 1.0.0", Status: ModuleSuccess, Duration: 2345 * time.Millisecond},
+				{Name: "fireflyframework-utils 1.0.0", Status: ModuleFailure, Duration: 65 * time.Second},
+				{Name: "Firefly Web 1.0.0", Status: ModuleSkipped},
+				{Name: "flywork-reactor 0", Status: ModuleSuccess, Duration: time.Millisecond},
+			},
+		},
+		{
+			name: "windows line endings and hour durations",
+			output: "[INFO] Reactor Summary:\r\n" +
+				"[INFO] Firefly Data 1.0.0 ..... SUCCESS [01:02 h]\r\n" +
+				"[INFO] BUILD SUCCESS\r\n",
+			want: []ModuleResult{
+				{Name: "Firefly Data 1.0.0", Status: ModuleSuccess, Duration: time.Hour + 2*time.Minute},
+			},
+		},
+		{
+			name: "the last summary wins",
+			output: "[INFO] Reactor Summary:\n" +
+				"[INFO] old 1.0.0 ..... FAILURE [  1.000 s]\n" +
+				"[INFO] BUILD FAILURE\n" +
+				"[INFO] Reactor Summary:\n" +
+				"[INFO] new 1.0.0 ..... SUCCESS [  3.000 s]\n" +
+				"[INFO] BUILD SUCCESS\n",
+			want: []ModuleResult{
+				{Name: "new 1.0.0", Status: ModuleSuccess, Duration: 3 * time.Second},
+			},
+		},
+		{
+			name: "unparsable duration",
+			output: "[INFO] Reactor Summary:\n" +
+				"[INFO] odd 1.0.0 ..... SUCCESS [ soon ]\n",
+			want: []ModuleResult{
+				{Name: "odd 1.0.0", Status: ModuleSuccess},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseReactorSummary([]byte(tt.output))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReactorSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReactorDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"2.345 s", 2345 * time.Millisecond},
+		{"  0.250 s", 250 * time.Millisecond},
+		{"01:05 min", 65 * time.Second},
+		{"01:02 h", time.Hour + 2*time.Minute},
+		{"12", 0},
+		{"x s", 0},
+		{"1:x min", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseReactorDuration(tt.in); got != tt.want {
+			t.Errorf("parseReactorDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
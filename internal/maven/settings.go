@@ -0,0 +1,123 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maven
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DefaultGoals are the goals of install builds when none are configured.
+var DefaultGoals = []string{"clean", "install"}
+
+// Settings configure Maven invocations beyond the JDK and heap: the goals of
+// install builds, the profiles to activate, -D properties, extra arguments
+// and environment variables. They come from the maven section of the config
+// (global) and the build settings of a repo in dag.yaml.
+type Settings struct {
+	Goals      []string          `yaml:"goals,omitempty"`      // Replace "clean install"; see Installs
+	Profiles   []string          `yaml:"profiles,omitempty"`   // Activated with -P
+	Properties map[string]string `yaml:"properties,omitempty"` // Passed as -Dkey=value (-Dkey when empty)
+	Args       []string          `yaml:"args,omitempty"`       // Appended to the command line, e.g. [-T, 1C]
+	Env        map[string]string `yaml:"env,omitempty"`        // Set in Maven's environment, e.g. MAVEN_OPTS; its -Xmx yields only to --mem-per-job
+	Offline    bool              `yaml:"offline,omitempty"`    // Run with -o and without -U
+}
+
+// Merge returns s with o layered on top: goals of o replace those of s,
+// profiles and arguments are appended, and the properties and environment
+// variables of o override those of s with the same name.
+func (s Settings) Merge(o Settings) Settings {
+	merged := Settings{
+		Goals:      s.Goals,
+		Profiles:   append(slices.Clone(s.Profiles), o.Profiles...),
+		Properties: maps.Clone(s.Properties),
+		Args:       append(slices.Clone(s.Args), o.Args...),
+		Env:        maps.Clone(s.Env),
+		Offline:    s.Offline || o.Offline,
+	}
+	if len(o.Goals) > 0 {
+		merged.Goals = o.Goals
+	}
+	if len(o.Properties) > 0 && merged.Properties == nil {
+		merged.Properties = make(map[string]string, len(o.Properties))
+	}
+	maps.Copy(merged.Properties, o.Properties)
+	if len(o.Env) > 0 && merged.Env == nil {
+		merged.Env = make(map[string]string, len(o.Env))
+	}
+	maps.Copy(merged.Env, o.Env)
+	return merged
+}
+
+// goals returns the configured goals, or DefaultGoals.
+func (s Settings) goals() []string {
+	if len(s.Goals) > 0 {
+		return s.Goals
+	}
+	return DefaultGoals
+}
+
+// Installs reports whether the goals install the built artifacts into
+// ~/.m2 (install or deploy). Builds with other goals, such as clean verify,
+// leave ~/.m2 untouched and must not be treated as up to date.
+func (s Settings) Installs() bool {
+	return slices.Contains(s.goals(), "install") || slices.Contains(s.goals(), "deploy")
+}
+
+// updateFlags returns the flags controlling remote repository access: -U
+// to check for updated snapshots, or -o when Maven runs offline — either
+// configured or because -o/--offline is among the arguments.
+func (s Settings) updateFlags(extraArgs []string) []string {
+	if s.Offline {
+		return []string{"-o"}
+	}
+	for _, arg := range slices.Concat(s.Args, extraArgs) {
+		if arg == "-o" || arg == "--offline" {
+			return nil
+		}
+	}
+	return []string{"-U"}
+}
+
+// flags returns the -P and -D flags followed by the extra arguments.
+func (s Settings) flags(profiles ...string) []string {
+	var flags []string
+	if all := slices.Concat(profiles, s.Profiles); len(all) > 0 {
+		flags = append(flags, "-P", strings.Join(all, ","))
+	}
+	for _, key := range slices.Sorted(maps.Keys(s.Properties)) {
+		if value := s.Properties[key]; value != "" {
+			flags = append(flags, "-D"+key+"="+value)
+		} else {
+			flags = append(flags, "-D"+key)
+		}
+	}
+	return append(flags, s.Args...)
+}
+
+// Inputs describes the settings that determine the output of a build, for
+// change detection and build cache keys: the flags, then the goals and
+// environment variables when they are configured.
+func (s Settings) Inputs() []string {
+	inputs := s.flags()
+	if len(s.Goals) > 0 {
+		inputs = append(inputs, "goals="+strings.Join(s.Goals, " "))
+	}
+	for _, key := range slices.Sorted(maps.Keys(s.Env)) {
+		inputs = append(inputs, "env:"+key+"="+s.Env[key])
+	}
+	return inputs
+}
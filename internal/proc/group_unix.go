@@ -0,0 +1,38 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package proc
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup starts cmd as the leader of a new process group and makes
+// cancellation send SIGTERM to the group, followed by SIGKILL if it is still
+// running after KillDelay.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := -cmd.Process.Pid
+		err := syscall.Kill(pgid, syscall.SIGTERM)
+		time.AfterFunc(KillDelay, func() {
+			_ = syscall.Kill(pgid, syscall.SIGKILL)
+		})
+		return err
+	}
+}
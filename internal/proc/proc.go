@@ -0,0 +1,43 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proc starts external tools (Maven, Git) so that cancelling their
+// context stops everything they spawned, not just the direct child.
+package proc
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// KillDelay is how long a cancelled process group gets to exit after
+// SIGTERM before it is killed.
+const KillDelay = 5 * time.Second
+
+// Command returns an exec.Cmd for name and args bound to ctx. When ctx can
+// be cancelled, the process runs in its own process group and cancelling
+// ctx terminates the whole group: Maven forks JVMs (e.g. surefire) that
+// would otherwise outlive it. A command with a context that is never
+// cancelled is started like exec.Command, staying in the terminal's process
+// group so that Ctrl-C still reaches it.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if ctx == nil || ctx.Done() == nil {
+		return exec.Command(name, args...)
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.WaitDelay = KillDelay + time.Second
+	return cmd
+}
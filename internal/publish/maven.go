@@ -15,16 +15,18 @@
 package publish
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
+	"sync"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/build"
-	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/runlog"
 )
 
 // PublishOptions configures a DAG-aware publish run.
@@ -36,20 +38,46 @@ type PublishOptions struct {
 	ForceAll    bool     // Publish all repos regardless of changes
 	TargetRepos []string // Publish specific repos only
 	DryRun      bool     // Show plan without publishing
+	Jobs        int      // Repos deployed concurrently (< 2 = sequential)
+	HeapMB      int      // -Xmx budget per Maven deploy via MAVEN_OPTS (0 = Maven default)
+	HeapSet     bool     // HeapMB replaces an -Xmx already in MAVEN_OPTS (--mem-per-job)
+
+	// FailurePolicy decides what happens to the rest of the plan after a
+	// deploy fails; the zero value skips the failed repo's dependents.
+	FailurePolicy dag.FailurePolicy
+
+	// Maven holds the global Maven settings; the overlay's settings of each
+	// repo are merged over them. Their goals do not apply to deploys.
+	Maven maven.Settings
+
+	// Logs keeps the Maven output of every deployed repo (nil = no logs).
+	Logs *runlog.Run
+
+	// OnOutput receives each line of Maven output as it is printed, with
+	// the repo that printed it. It is called concurrently when Jobs > 1.
+	OnOutput func(repo, line string)
 }
 
 // PublishResult holds the outcome of publishing a single repository.
 type PublishResult struct {
-	Repo    string
-	Skipped bool
-	Error   error
-	LogFile string
+	Repo      string
+	Skipped   bool
+	Blocked   bool   // Not deployed because BlockedBy failed
+	BlockedBy string // Failed repo that blocked this one
+	// Interrupted is set when the publish was cancelled before the repo
+	// was deployed; Error is then the context's error.
+	Interrupted bool
+	Error       error
+	LogFile     string
 }
 
-// PublishStartCallback is invoked before each repo publish begins.
+// PublishStartCallback is invoked before each repo publish begins. Callbacks
+// are never invoked concurrently, even when repos are deployed in parallel.
 type PublishStartCallback func(layer int, repo string, index int, total int)
 
-// PublishDoneCallback is invoked after each repo publish completes.
+// PublishDoneCallback is invoked after each repo publish completes, and for
+// every repo blocked by a failure or not started because the publish was
+// cancelled (without a matching PublishStartCallback).
 type PublishDoneCallback func(layer int, repo string, index int, total int, result PublishResult)
 
 // DeployRepo returns the Maven altDeploymentRepository value for a given repo.
@@ -58,8 +86,22 @@ func DeployRepo(githubOrg, repoName string) string {
 }
 
 // PublishAllDAG publishes all Maven repos in DAG order with change detection.
-func PublishAllDAG(opts PublishOptions, onStart PublishStartCallback, onDone PublishDoneCallback) ([]PublishResult, [][]string, error) {
-	g := dag.FrameworkGraph()
+// Cancelling ctx terminates the running deploys, marks their repos
+// interrupted in the build manifest and starts no new ones; PublishAllDAG
+// then returns the results together with the context's error.
+func PublishAllDAG(ctx context.Context, opts PublishOptions, onStart PublishStartCallback, onDone PublishDoneCallback) ([]PublishResult, [][]string, error) {
+	g, err := dag.Load(opts.ReposDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	overlay, err := dag.LoadOverlay(opts.ReposDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	cat, err := catalog.Load(opts.ReposDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	manifest, err := build.LoadManifest(build.DefaultManifestPath())
 	if err != nil {
@@ -123,70 +165,98 @@ func PublishAllDAG(opts PublishOptions, onStart PublishStartCallback, onDone Pub
 		return results, layers, nil
 	}
 
-	results := make([]PublishResult, 0, total)
-	idx := 0
-
-	for layerIdx, layer := range layers {
-		for _, repo := range layer {
-			idx++
-			dir := filepath.Join(opts.ReposDir, repo)
+	results := make([]PublishResult, total)
+	var mu sync.Mutex // serializes callbacks and manifest saves
 
-			if onStart != nil {
-				onStart(layerIdx, repo, idx, total)
-			}
-
-			// Skip repos without pom.xml
-			pomPath := filepath.Join(dir, "pom.xml")
-			if _, serr := os.Stat(pomPath); os.IsNotExist(serr) {
-				r := PublishResult{Repo: repo, Skipped: true}
-				results = append(results, r)
-				if onDone != nil {
-					onDone(layerIdx, repo, idx, total, r)
-				}
-				continue
-			}
-
-			deployTarget := DeployRepo(opts.GithubOrg, repo)
-			sha, _ := git.HeadSHA(dir)
+	// done stores a result, saves the manifest and reports the result.
+	done := func(layerIdx, idx int, r PublishResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[idx-1] = r
+		_ = manifest.Save()
+		if onDone != nil {
+			onDone(layerIdx, r.Repo, idx, total, r)
+		}
+	}
 
-			output, deployErr := maven.DeployQuietOutput(dir, opts.JavaHome, opts.SkipTests, deployTarget)
+	err = dag.Schedule(sub, opts.Jobs, opts.FailurePolicy, func(layerIdx int, repo string, idx int) bool {
+		dir := filepath.Join(opts.ReposDir, repo)
 
-			var logFile string
-			if deployErr != nil && len(output) > 0 {
-				logFile = writePublishLog(repo, output)
-			}
+		if ctx.Err() != nil {
+			done(layerIdx, idx, PublishResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return false
+		}
 
-			if deployErr == nil {
-				manifest.MarkSuccess(repo, sha)
-			} else {
-				manifest.MarkFailed(repo, sha, deployErr)
-			}
-			_ = manifest.Save()
+		mu.Lock()
+		if onStart != nil {
+			onStart(layerIdx, repo, idx, total)
+		}
+		mu.Unlock()
 
-			r := PublishResult{Repo: repo, Error: deployErr, LogFile: logFile}
-			results = append(results, r)
+		// Skip repos without pom.xml or that the overlay or the catalog
+		// mark unpublishable
+		settings := overlay.Settings(repo)
+		pomPath := filepath.Join(dir, "pom.xml")
+		if _, serr := os.Stat(pomPath); os.IsNotExist(serr) || !settings.Publishable() || !cat.Publishable(repo) {
+			r := PublishResult{Repo: repo, Skipped: true}
+			mu.Lock()
+			results[idx-1] = r
 			if onDone != nil {
 				onDone(layerIdx, repo, idx, total, r)
 			}
+			mu.Unlock()
+			return true
 		}
-	}
 
-	return results, layers, nil
-}
+		deployTarget := DeployRepo(opts.GithubOrg, repo)
+		if settings.DeployRepository != "" {
+			deployTarget = settings.DeployRepository
+		}
+		sha, _ := git.HeadSHA(dir)
+		fingerprint, _ := build.Fingerprint(dir)
 
-// writePublishLog writes deploy output to ~/.flywork/logs/<repo>-publish.log.
-func writePublishLog(repo string, output []byte) string {
-	logsDir := filepath.Join(config.FlyworkHome(), "logs")
-	if err := os.MkdirAll(logsDir, 0755); err != nil {
-		return ""
-	}
-	logFile := filepath.Join(logsDir, repo+"-publish.log")
+		runOpts := maven.RunOptions{
+			JavaHome: opts.JavaHome,
+			HeapMB:   opts.HeapMB,
+			HeapSet:  opts.HeapSet,
+			Maven:    opts.Maven.Merge(settings.Maven()),
+			LogFile:  opts.Logs.Path(repo),
+		}
+		if opts.OnOutput != nil {
+			runOpts.Tail = func(line string) { opts.OnOutput(repo, line) }
+		}
+		_, deployErr := maven.DeployWithOptions(ctx, dir, runOpts, settings.ResolveSkipTests(opts.SkipTests), deployTarget)
+		if deployErr != nil && ctx.Err() != nil {
+			opts.Logs.Record(repo, ctx.Err())
+			manifest.MarkInterrupted(repo, sha)
+			done(layerIdx, idx, PublishResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return false
+		}
 
-	header := fmt.Sprintf("=== Publish log for %s ===\n=== %s ===\n\n", repo, time.Now().Format(time.RFC3339))
-	content := append([]byte(header), output...)
+		logFile := opts.Logs.Record(repo, deployErr)
+
+		if deployErr == nil {
+			manifest.MarkSuccess(repo, sha, fingerprint)
+			if artifacts, err := maven.InstalledArtifacts(dir); err == nil {
+				manifest.RecordArtifacts(repo, artifacts)
+			}
+		} else {
+			manifest.MarkFailed(repo, sha, deployErr)
+		}
 
-	if err := os.WriteFile(logFile, content, 0644); err != nil {
-		return ""
+		done(layerIdx, idx, PublishResult{Repo: repo, Error: deployErr, LogFile: logFile})
+		return deployErr == nil
+	}, func(layerIdx int, repo string, idx int, blockedBy string) {
+		if ctx.Err() != nil {
+			done(layerIdx, idx, PublishResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return
+		}
+		manifest.MarkBlocked(repo, blockedBy)
+		done(layerIdx, idx, PublishResult{Repo: repo, Blocked: true, BlockedBy: blockedBy})
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	return logFile
+
+	return results, layers, ctx.Err()
 }
@@ -15,17 +15,21 @@
 package publish
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/proc"
 )
 
 // PublishPython builds a Python package with uv and uploads the wheel and sdist
 // as GitHub Release assets. This avoids PyPI and uses GitHub Releases as the
 // distribution channel, which is the standard approach for org-internal packages.
-func PublishPython(repoDir, githubOrg string) error {
+// Cancelling ctx stops the build or upload.
+func PublishPython(ctx context.Context, repoDir, githubOrg string) error {
 	// Check uv is available
 	if _, err := exec.LookPath("uv"); err != nil {
 		return fmt.Errorf("uv not found on PATH — install it with: curl -LsSf https://astral.sh/uv/install.sh | sh")
@@ -37,7 +41,7 @@ func PublishPython(repoDir, githubOrg string) error {
 	}
 
 	// Build the package
-	buildCmd := exec.Command("uv", "build")
+	buildCmd := proc.Command(ctx, "uv", "build")
 	buildCmd.Dir = repoDir
 	buildCmd.Stdout = os.Stdout
 	buildCmd.Stderr = os.Stderr
@@ -78,7 +82,7 @@ func PublishPython(repoDir, githubOrg string) error {
 	args = append(args, files...)
 	args = append(args, "--clobber")
 
-	uploadCmd := exec.Command("gh", args...)
+	uploadCmd := proc.Command(ctx, "gh", args...)
 	uploadCmd.Dir = repoDir
 	uploadCmd.Stdout = os.Stdout
 	uploadCmd.Stderr = os.Stderr
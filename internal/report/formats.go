@@ -0,0 +1,163 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// JSON renders the report as indented JSON.
+func (rep *Report) JSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rep); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// JUnit XML elements, as understood by Jenkins, GitLab and GitHub Actions
+// test reporters.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// JUnit renders the report as JUnit XML: one test suite per DAG layer and
+// one test case per repo. Failed repos are failures, interrupted repos are
+// errors, and skipped and blocked repos are skipped test cases.
+func (rep *Report) JUnit() ([]byte, error) {
+	root := junitSuites{
+		Name: "flywork " + rep.Command,
+		Time: seconds(rep.DurationMs),
+	}
+	var suite *junitSuite
+	var suiteMs []int64
+	for _, r := range rep.Repos {
+		name := fmt.Sprintf("layer-%d", r.Layer)
+		if suite == nil || suite.Name != name {
+			root.Suites = append(root.Suites, junitSuite{Name: name, Timestamp: r.StartedAt.UTC().Format("2006-01-02T15:04:05")})
+			suite = &root.Suites[len(root.Suites)-1]
+			suiteMs = append(suiteMs, 0)
+		}
+		suiteMs[len(suiteMs)-1] += r.DurationMs
+
+		tc := junitCase{
+			Name:      r.Name,
+			ClassName: fmt.Sprintf("flywork.%s.%s", rep.Command, name),
+			Time:      seconds(r.DurationMs),
+		}
+		var out []string
+		if r.SHA != "" {
+			out = append(out, "SHA: "+r.SHA)
+		}
+		if len(r.Modules) > 0 {
+			out = append(out, "Modules: "+strings.Join(r.Modules, ", "))
+		}
+		if r.LogFile != "" {
+			out = append(out, "Log: "+r.LogFile)
+		}
+
+		switch r.Status {
+		case StatusFailed:
+			body := []string{r.Error}
+			if d := r.Diagnosis; d != nil {
+				tc.Failure = &junitMessage{Message: d.Summary()}
+				body = append(append(append(body, d.Summary()), d.Details(0)...), d.Hints...)
+			} else {
+				tc.Failure = &junitMessage{Message: r.Error}
+			}
+			tc.Failure.Type = string(r.Status)
+			tc.Failure.Body = strings.Join(append(body, out...), "\n")
+			suite.Failures++
+		case StatusInterrupted:
+			tc.Error = &junitMessage{Message: "interrupted", Type: string(r.Status)}
+			suite.Errors++
+		case StatusBlocked:
+			tc.Skipped = &junitMessage{Message: "blocked by " + r.BlockedBy}
+			suite.Skipped++
+		case StatusSkipped:
+			tc.Skipped = &junitMessage{Message: "skipped"}
+			suite.Skipped++
+		case StatusCached:
+			out = append(out, "Restored from the build cache")
+		}
+		if tc.Failure == nil {
+			tc.SystemOut = strings.Join(out, "\n")
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+	}
+
+	for i := range root.Suites {
+		s := &root.Suites[i]
+		s.Time = seconds(suiteMs[i])
+		root.Tests += s.Tests
+		root.Failures += s.Failures
+		root.Errors += s.Errors
+		root.Skipped += s.Skipped
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// seconds formats milliseconds as the decimal seconds JUnit expects.
+func seconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
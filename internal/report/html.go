@@ -0,0 +1,214 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+)
+
+// timelineRow is one repo bar of the HTML timeline.
+type timelineRow struct {
+	Repo
+	Left     float64 // Start offset in percent of the run
+	Width    float64 // Duration in percent of the run
+	Offset   string
+	Duration string
+}
+
+// timelineTick is a time label on the timeline axis.
+type timelineTick struct {
+	Left  float64
+	Label string
+}
+
+type htmlData struct {
+	*Report
+	Title        string
+	Wall         string
+	Busy         string
+	Idle         string
+	Utilization  int
+	CriticalPath string
+	CriticalLen  int
+	Rows         []timelineRow
+	Ticks        []timelineTick
+}
+
+// HTML renders the report as a self-contained HTML page with a Gantt-style
+// timeline of the run: one bar per repo, positioned at the time it started
+// and as long as it ran, with the critical path outlined. Gaps between
+// bars on the critical path and the idle share of the --jobs workers show
+// where the run waited.
+func (rep *Report) HTML() ([]byte, error) {
+	total := max(rep.DurationMs, 1)
+	data := htmlData{
+		Report: rep,
+		Title:  fmt.Sprintf("flywork %s — %s", rep.Command, rep.StartedAt.Format("2006-01-02 15:04:05")),
+		Wall:   formatMs(rep.DurationMs),
+	}
+
+	var busy, critical int64
+	for _, r := range rep.Repos {
+		offset := r.StartedAt.Sub(rep.StartedAt).Milliseconds()
+		data.Rows = append(data.Rows, timelineRow{
+			Repo:     r,
+			Left:     percent(offset, total),
+			Width:    max(percent(r.DurationMs, total), 0.3),
+			Offset:   formatMs(offset),
+			Duration: formatMs(r.DurationMs),
+		})
+		busy += r.DurationMs
+		if r.Critical {
+			critical += r.DurationMs
+			data.CriticalLen++
+		}
+	}
+	sort.SliceStable(data.Rows, func(i, j int) bool {
+		return data.Rows[i].StartedAt.Before(data.Rows[j].StartedAt)
+	})
+
+	capacity := int64(rep.Jobs) * total
+	data.Busy = formatMs(busy)
+	data.Idle = formatMs(max(capacity-busy, 0))
+	data.Utilization = int(min(100, busy*100/capacity))
+	data.CriticalPath = formatMs(critical)
+	for i := 0; i <= 4; i++ {
+		data.Ticks = append(data.Ticks, timelineTick{Left: float64(i * 25), Label: formatMs(total * int64(i) / 4)})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ShortSHA returns the abbreviated commit SHA shown in the HTML report.
+func (r Repo) ShortSHA() string {
+	if len(r.SHA) > 12 {
+		return r.SHA[:12]
+	}
+	return r.SHA
+}
+
+func percent(part, total int64) float64 {
+	return float64(part) * 100 / float64(total)
+}
+
+// formatMs formats a duration in milliseconds for display.
+func formatMs(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #212529; }
+  h1 { font-size: 1.4em; margin-bottom: 0.2em; }
+  .muted { color: #6C757D; }
+  .result { font-weight: bold; text-transform: uppercase; }
+  .result.success { color: #28A745; } .result.failed { color: #DC3545; } .result.interrupted { color: #6F42C1; }
+  .stats { display: flex; flex-wrap: wrap; gap: 2em; margin: 1.2em 0; }
+  .stats div b { display: block; font-size: 1.3em; }
+  table { border-collapse: collapse; width: 100%; font-size: 0.9em; }
+  th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #DEE2E6; vertical-align: middle; }
+  th { background: #F8F9FA; }
+  td.name { white-space: nowrap; }
+  td.gantt { width: 55%; position: relative; }
+  .track { position: relative; height: 16px; background: #F1F3F5; }
+  .bar { position: absolute; top: 0; height: 16px; border-radius: 2px; }
+  .bar.critical { outline: 2px solid #212529; z-index: 1; }
+  .axis { position: relative; height: 1.2em; font-size: 0.8em; color: #6C757D; }
+  .axis span { position: absolute; transform: translateX(-50%); }
+  .axis span:first-child { transform: none; }
+  .axis span:last-child { transform: translateX(-100%); }
+  .status { font-weight: bold; }
+  .built, .published, .pulled { background: #28A745; } .cached { background: #17A2B8; }
+  .skipped { background: #ADB5BD; } .failed { background: #DC3545; }
+  .blocked { background: #FD7E14; } .interrupted { background: #6F42C1; }
+  span.status.built, span.status.published, span.status.pulled { color: #28A745; background: none; }
+  span.status.cached { color: #17A2B8; background: none; } span.status.skipped { color: #6C757D; background: none; }
+  span.status.failed { color: #DC3545; background: none; } span.status.blocked { color: #FD7E14; background: none; }
+  span.status.interrupted { color: #6F42C1; background: none; }
+  .legend span { display: inline-block; margin-right: 1em; }
+  .legend i { display: inline-block; width: 12px; height: 12px; margin-right: 4px; vertical-align: middle; }
+  code { font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div>Result: <span class="result {{.Result}}">{{.Result}}</span></div>
+
+<div class="stats">
+  <div><b>{{.Wall}}</b><span class="muted">wall time</span></div>
+  <div><b>{{.Busy}}</b><span class="muted">repo time</span></div>
+  <div><b>{{.Jobs}}</b><span class="muted">jobs</span></div>
+  <div><b>{{.Utilization}}%</b><span class="muted">worker utilization</span></div>
+  <div><b>{{.Idle}}</b><span class="muted">idle worker time</span></div>
+  <div><b>{{.CriticalPath}}</b><span class="muted">critical path ({{.CriticalLen}} repos)</span></div>
+  {{range $status, $n := .Summary}}<div><b>{{$n}}</b><span class="muted">{{$status}}</span></div>
+  {{end}}
+</div>
+
+<p class="legend">
+  <span><i class="built"></i>built</span><span><i class="cached"></i>cached</span>
+  <span><i class="skipped"></i>skipped</span><span><i class="failed"></i>failed</span>
+  <span><i class="blocked"></i>blocked</span><span><i class="interrupted"></i>interrupted</span>
+  <span><i class="built" style="outline: 2px solid #212529"></i>critical path</span>
+</p>
+
+<table>
+  <tr>
+    <th>Repository</th><th>Layer</th><th>Status</th><th>Start</th><th>Duration</th>
+    <th><div class="axis">{{range .Ticks}}<span style="left: {{.Left}}%">{{.Label}}</span>{{end}}</div></th>
+  </tr>
+  {{range .Rows}}
+  <tr>
+    <td class="name" title="{{.SHA}}">{{.Name}}{{if .Modules}} <span class="muted">({{range $i, $m := .Modules}}{{if $i}}, {{end}}{{$m}}{{end}})</span>{{end}}</td>
+    <td>{{.Layer}}</td>
+    <td><span class="status {{.Status}}">{{.Status}}</span></td>
+    <td>+{{.Offset}}</td>
+    <td>{{.Duration}}</td>
+    <td class="gantt"><div class="track"><div class="bar {{.Status}}{{if .Critical}} critical{{end}}" style="left: {{printf "%.2f" .Left}}%; width: {{printf "%.2f" .Width}}%" title="{{.Name}}: {{.Status}}, {{.Duration}}"></div></div></td>
+  </tr>
+  {{end}}
+</table>
+
+<h2>Details</h2>
+<table>
+  <tr><th>Repository</th><th>SHA</th><th>Depends on</th><th>Details</th></tr>
+  {{range .Repos}}
+  <tr>
+    <td class="name">{{.Name}}</td>
+    <td><code>{{.ShortSHA}}</code></td>
+    <td>{{range $i, $d := .DependsOn}}{{if $i}}, {{end}}{{$d}}{{end}}</td>
+    <td>{{if .Error}}{{.Error}}<br>{{end}}{{with .Diagnosis}}<b>{{.Summary}}</b><br>{{range .Details 10}}<code>{{.}}</code><br>{{end}}{{range .Hints}}→ {{.}}<br>{{end}}{{end}}{{if .BlockedBy}}blocked by {{.BlockedBy}}<br>{{end}}{{if .LogFile}}<code>{{.LogFile}}</code>{{end}}</td>
+  </tr>
+  {{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,308 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report records the per-repo outcome of a build, setup, update or
+// publish run and writes it as JSON, JUnit XML or an HTML timeline for CI.
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
+	"github.com/fireflyframework/fireflyframework-cli/internal/git"
+	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+)
+
+// Report formats supported by Write.
+const (
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatHTML  = "html"
+)
+
+// Formats lists every supported report format.
+var Formats = []string{FormatJSON, FormatJUnit, FormatHTML}
+
+// extensions maps report formats to their default file extensions.
+var extensions = map[string]string{
+	FormatJSON:  ".json",
+	FormatJUnit: ".xml",
+	FormatHTML:  ".html",
+}
+
+// Status is the outcome of a single repo in a run.
+type Status string
+
+const (
+	StatusBuilt       Status = "built"       // Built or installed by Maven
+	StatusCached      Status = "cached"      // Restored from the build cache
+	StatusPublished   Status = "published"   // Deployed by publish
+	StatusPulled      Status = "pulled"      // Pulled by update --pull-only
+	StatusSkipped     Status = "skipped"     // Up to date, not cloned or excluded
+	StatusFailed      Status = "failed"      // The repo's own step failed
+	StatusBlocked     Status = "blocked"     // Not processed because a dependency failed
+	StatusInterrupted Status = "interrupted" // Cancelled by Ctrl-C
+)
+
+// Run results summarizing a whole report.
+const (
+	ResultSuccess     = "success"
+	ResultFailed      = "failed"
+	ResultInterrupted = "interrupted"
+)
+
+// Repo is the outcome of one repository.
+type Repo struct {
+	Name       string    `json:"name"`
+	Status     Status    `json:"status"`
+	Layer      int       `json:"layer"`
+	SHA        string    `json:"sha,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	LogFile    string    `json:"log_file,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	BlockedBy  string    `json:"blocked_by,omitempty"`
+	Modules    []string  `json:"modules,omitempty"`    // Submodules of an incremental build
+	DependsOn  []string  `json:"depends_on,omitempty"` // Dependencies that are part of the run
+	Critical   bool      `json:"critical,omitempty"`   // On the critical path of the run
+	// Diagnosis is the analysis of the Maven output of a failed repo.
+	Diagnosis *maven.Diagnosis `json:"diagnosis,omitempty"`
+}
+
+// Finished returns when the repo finished.
+func (r Repo) Finished() time.Time {
+	return r.StartedAt.Add(time.Duration(r.DurationMs) * time.Millisecond)
+}
+
+// Report is the outcome of a whole run.
+type Report struct {
+	Command    string         `json:"command"`
+	Result     string         `json:"result"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	DurationMs int64          `json:"duration_ms"`
+	Jobs       int            `json:"jobs"`
+	Summary    map[Status]int `json:"summary"`
+	Repos      []Repo         `json:"repos"`
+}
+
+// Recorder collects the results of a run as the command's start and done
+// callbacks report them. It is safe for concurrent use.
+type Recorder struct {
+	mu       sync.Mutex
+	command  string
+	reposDir string
+	graph    *dag.Graph
+	jobs     int
+	started  time.Time
+	starts   map[string]time.Time
+	repos    map[string]*Repo
+}
+
+// NewRecorder returns a Recorder for a run of command over the repos in
+// reposDir. g supplies the dependencies used to find the critical path; it
+// may be nil.
+func NewRecorder(command, reposDir string, g *dag.Graph, jobs int) *Recorder {
+	return &Recorder{
+		command:  command,
+		reposDir: reposDir,
+		graph:    g,
+		jobs:     max(jobs, 1),
+		started:  time.Now(),
+		starts:   make(map[string]time.Time),
+		repos:    make(map[string]*Repo),
+	}
+}
+
+// SetGraph replaces the dependency graph, for runs that only know the
+// workspace graph once the repos are cloned.
+func (rec *Recorder) SetGraph(g *dag.Graph) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.graph = g
+}
+
+// Start records that work on repo began.
+func (rec *Recorder) Start(repo string) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.starts[repo] = time.Now()
+}
+
+// Finish records the outcome of a repo. Its start time and duration are
+// taken from the matching Start; repos that never started get a zero
+// duration. A skipped result never replaces an earlier result for the same
+// repo, so a later phase or retry pass that passes over a repo keeps what
+// the earlier one recorded.
+func (rec *Recorder) Finish(r Repo) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if _, seen := rec.repos[r.Name]; seen && r.Status == StatusSkipped {
+		return
+	}
+	now := time.Now()
+	r.StartedAt = now
+	if start, ok := rec.starts[r.Name]; ok {
+		r.StartedAt = start
+		r.DurationMs = now.Sub(start).Milliseconds()
+		delete(rec.starts, r.Name)
+	}
+	rec.repos[r.Name] = &r
+}
+
+// Report assembles the report of the run so far. The SHA of each repo is
+// read from its checkout when the caller did not record one.
+func (rec *Recorder) Report() *Report {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rep := &Report{
+		Command:    rec.command,
+		Result:     ResultSuccess,
+		StartedAt:  rec.started,
+		FinishedAt: time.Now(),
+		Jobs:       rec.jobs,
+		Summary:    make(map[Status]int),
+		Repos:      make([]Repo, 0, len(rec.repos)),
+	}
+	rep.DurationMs = rep.FinishedAt.Sub(rep.StartedAt).Milliseconds()
+
+	for _, r := range rec.repos {
+		repo := *r
+		if repo.SHA == "" && rec.reposDir != "" {
+			repo.SHA, _ = git.HeadSHA(filepath.Join(rec.reposDir, repo.Name))
+		}
+		if rec.graph != nil && rec.graph.HasNode(repo.Name) {
+			for _, dep := range rec.graph.DependenciesOf(repo.Name) {
+				if _, ok := rec.repos[dep]; ok {
+					repo.DependsOn = append(repo.DependsOn, dep)
+				}
+			}
+			sort.Strings(repo.DependsOn)
+		}
+		rep.Summary[repo.Status]++
+		switch repo.Status {
+		case StatusInterrupted:
+			rep.Result = ResultInterrupted
+		case StatusFailed, StatusBlocked:
+			if rep.Result == ResultSuccess {
+				rep.Result = ResultFailed
+			}
+		}
+		rep.Repos = append(rep.Repos, repo)
+	}
+
+	sort.Slice(rep.Repos, func(i, j int) bool {
+		a, b := rep.Repos[i], rep.Repos[j]
+		if a.Layer != b.Layer {
+			return a.Layer < b.Layer
+		}
+		if !a.StartedAt.Equal(b.StartedAt) {
+			return a.StartedAt.Before(b.StartedAt)
+		}
+		return a.Name < b.Name
+	})
+	markCriticalPath(rep.Repos)
+	return rep
+}
+
+// markCriticalPath marks the chain of repos that determined the run's wall
+// time: starting from the repo that finished last, it repeatedly follows the
+// dependency that finished last, since that is what the repo waited for.
+// Skipped and blocked repos did no work and are never on the path.
+func markCriticalPath(repos []Repo) {
+	worked := func(r Repo) bool {
+		return r.Status != StatusSkipped && r.Status != StatusBlocked && r.DurationMs > 0
+	}
+	index := make(map[string]int, len(repos))
+	last := -1
+	for i, r := range repos {
+		index[r.Name] = i
+		if worked(r) && (last < 0 || r.Finished().After(repos[last].Finished())) {
+			last = i
+		}
+	}
+	for cur := last; cur >= 0; {
+		repos[cur].Critical = true
+		next := -1
+		for _, dep := range repos[cur].DependsOn {
+			i := index[dep]
+			if worked(repos[i]) && !repos[i].Critical &&
+				(next < 0 || repos[i].Finished().After(repos[next].Finished())) {
+				next = i
+			}
+		}
+		cur = next
+	}
+}
+
+// ResolveTarget validates the --report and --report-file flags. A missing
+// format is inferred from the file extension; a missing file defaults to
+// flywork-<command>-report.<ext> in the working directory. It returns an
+// empty format when no report was requested.
+func ResolveTarget(command, format, path string) (string, string, error) {
+	if format == "" && path == "" {
+		return "", "", nil
+	}
+	if format == "" {
+		for f, ext := range extensions {
+			if strings.EqualFold(filepath.Ext(path), ext) {
+				format = f
+			}
+		}
+		if format == "" {
+			return "", "", fmt.Errorf("cannot infer the report format of %s — use --report (%s)", path, strings.Join(Formats, ", "))
+		}
+	}
+	ext, ok := extensions[format]
+	if !ok {
+		return "", "", fmt.Errorf("unknown report format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+	if path == "" {
+		path = fmt.Sprintf("flywork-%s-report%s", command, ext)
+	}
+	return format, path, nil
+}
+
+// Write renders the report in format and writes it to path, creating the
+// parent directory if needed.
+func (rep *Report) Write(format, path string) error {
+	var data []byte
+	var err error
+	switch format {
+	case FormatJSON:
+		data, err = rep.JSON()
+	case FormatJUnit:
+		data, err = rep.JUnit()
+	case FormatHTML:
+		data, err = rep.HTML()
+	default:
+		return fmt.Errorf("unknown report format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
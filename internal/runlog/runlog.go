@@ -0,0 +1,335 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runlog keeps the Maven output of every build, setup, update and
+// publish run: each run gets its own directory under ~/.flywork/logs with one
+// log file per repo and a run.json index, and old runs are pruned by a
+// retention policy.
+package runlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+)
+
+// Log statuses recorded per repo.
+const (
+	StatusSuccess     = "success"
+	StatusFailed      = "failed"
+	StatusInterrupted = "interrupted"
+)
+
+const indexFile = "run.json"
+
+// Dir returns the directory holding the run directories (~/.flywork/logs).
+func Dir() string {
+	return filepath.Join(config.FlyworkHome(), "logs")
+}
+
+// Entry is the log of one repo in a run.
+type Entry struct {
+	Repo   string    `json:"repo"`
+	Status string    `json:"status"`
+	File   string    `json:"file"` // Relative to the run directory
+	Time   time.Time `json:"time"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Run is one invocation of a command whose repo logs are kept together.
+// Its methods are safe for concurrent use; a nil *Run discards all logs.
+type Run struct {
+	ID         string    `json:"id"`
+	Command    string    `json:"command"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitzero"`
+	Entries    []Entry   `json:"repos"`
+
+	mu  sync.Mutex
+	dir string
+}
+
+// Retention bounds the runs kept in Dir. Zero values disable a limit.
+type Retention struct {
+	Runs   int           // Most recent runs to keep
+	MaxAge time.Duration // Remove runs started longer ago than this
+}
+
+// RetentionFromConfig returns the retention policy configured with
+// log_retention_runs and log_retention_days.
+func RetentionFromConfig(cfg *config.Config) Retention {
+	return Retention{
+		Runs:   cfg.LogRetentionRuns,
+		MaxAge: time.Duration(cfg.LogRetentionDays) * 24 * time.Hour,
+	}
+}
+
+// Start creates the log directory of a new run of command, named after the
+// current time and the command (e.g. 20260316-142501-build), and prunes the
+// runs that fall outside the retention policy.
+func Start(command string, keep Retention) (*Run, error) {
+	started := time.Now()
+	base := started.Format("20060102-150405") + "-" + command
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	id := base
+	for n := 2; ; n++ {
+		err := os.Mkdir(filepath.Join(Dir(), id), 0755)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create run log directory: %w", err)
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	r := &Run{ID: id, Command: command, StartedAt: started, Entries: []Entry{}, dir: filepath.Join(Dir(), id)}
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	// The new run is the most recent one, so pruning never removes it.
+	_, _ = Prune(keep)
+	return r, nil
+}
+
+// Dir returns the run's log directory.
+func (r *Run) Dir() string {
+	if r == nil {
+		return ""
+	}
+	return r.dir
+}
+
+// Path returns the log file of repo in the run, or "" for a nil run.
+func (r *Run) Path(repo string) string {
+	if r == nil {
+		return ""
+	}
+	return filepath.Join(r.dir, repo+".log")
+}
+
+// Record adds the outcome of repo to the run index — failed when err is
+// set, interrupted when err is a context cancellation — and appends it to
+// the repo's log, which Maven streamed to Path(repo). It returns the log
+// file, or "" when there is none.
+func (r *Run) Record(repo string, err error) string {
+	if r == nil {
+		return ""
+	}
+	e := Entry{Repo: repo, Status: statusOf(err), File: repo + ".log", Time: time.Now()}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	path := r.Path(repo)
+	if f, ferr := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0); ferr == nil {
+		fmt.Fprintf(f, "\n=== %s %s — %s (run %s) ===\n", r.Command, e.Status, e.Time.Format(time.RFC3339), r.ID)
+		_ = f.Close()
+	} else {
+		path = ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	replaced := false
+	for i := range r.Entries {
+		if r.Entries[i].Repo == repo {
+			r.Entries[i], replaced = e, true
+		}
+	}
+	if !replaced {
+		r.Entries = append(r.Entries, e)
+	}
+	_ = r.saveLocked()
+	return path
+}
+
+// Finish records the end of the run. A run that logged nothing, e.g. a
+// build with everything up to date, is removed instead.
+func (r *Run) Finish() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.Entries) == 0 {
+		_ = os.RemoveAll(r.dir)
+		return
+	}
+	r.FinishedAt = time.Now()
+	_ = r.saveLocked()
+}
+
+// Entry returns the log entry of repo, if the run has one.
+func (r *Run) Entry(repo string) (Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.Entries {
+		if e.Repo == repo {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Counts returns the number of repos logged with each status.
+func (r *Run) Counts() map[string]int {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int)
+	for _, e := range r.Entries {
+		counts[e.Status]++
+	}
+	return counts
+}
+
+func (r *Run) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saveLocked()
+}
+
+func (r *Run) saveLocked() error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(filepath.Join(r.dir, indexFile), data, 0644)
+}
+
+func statusOf(err error) string {
+	switch {
+	case err == nil:
+		return StatusSuccess
+	case errors.Is(err, context.Canceled):
+		return StatusInterrupted
+	default:
+		return StatusFailed
+	}
+}
+
+// Load reads the run with the given ID.
+func Load(id string) (*Run, error) {
+	dir := filepath.Join(Dir(), id)
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no run %q in %s", id, Dir())
+		}
+		return nil, err
+	}
+	r := &Run{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid run index %s: %w", filepath.Join(dir, indexFile), err)
+	}
+	r.dir = dir
+	return r, nil
+}
+
+// List returns the recorded runs, newest first. Directories without a
+// readable run index are ignored.
+func List() ([]*Run, error) {
+	entries, err := os.ReadDir(Dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var runs []*Run
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if r, err := Load(e.Name()); err == nil {
+			runs = append(runs, r)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].StartedAt.After(runs[j].StartedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	return runs, nil
+}
+
+// Find returns the run with the given ID, or the latest run when id is
+// empty. An ID may be abbreviated to any unique prefix.
+func Find(id string) (*Run, error) {
+	runs, err := List()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		if len(runs) == 0 {
+			return nil, fmt.Errorf("no runs logged in %s", Dir())
+		}
+		return runs[0], nil
+	}
+	var match []*Run
+	for _, r := range runs {
+		if r.ID == id {
+			return r, nil
+		}
+		if strings.HasPrefix(r.ID, id) {
+			match = append(match, r)
+		}
+	}
+	switch len(match) {
+	case 0:
+		return nil, fmt.Errorf("no run %q in %s — see 'flywork logs list'", id, Dir())
+	case 1:
+		return match[0], nil
+	default:
+		return nil, fmt.Errorf("run ID %q is ambiguous (%d runs match)", id, len(match))
+	}
+}
+
+// Prune removes the runs that fall outside the retention policy and returns
+// their IDs.
+func Prune(keep Retention) ([]string, error) {
+	runs, err := List()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-keep.MaxAge)
+	var removed []string
+	for i, r := range runs {
+		tooMany := keep.Runs > 0 && i >= keep.Runs
+		tooOld := keep.MaxAge > 0 && r.StartedAt.Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.RemoveAll(r.dir); err != nil {
+			return removed, err
+		}
+		removed = append(removed, r.ID)
+	}
+	return removed, nil
+}
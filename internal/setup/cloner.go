@@ -15,61 +15,21 @@
 package setup
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/git"
 )
 
-// FrameworkRepos lists all fireflyframework repos in dependency order (flat fallback).
-var FrameworkRepos = []string{
-	"fireflyframework-parent",
-	"fireflyframework-bom",
-	"fireflyframework-utils",
-	"fireflyframework-validators",
-	"fireflyframework-plugins",
-	"fireflyframework-observability",
-	"fireflyframework-cache",
-	"fireflyframework-r2dbc",
-	"fireflyframework-eda",
-	"fireflyframework-cqrs",
-	"fireflyframework-eventsourcing",
-	"fireflyframework-transactional-engine",
-	"fireflyframework-client",
-	"fireflyframework-web",
-	"fireflyframework-core",
-	"fireflyframework-domain",
-	"fireflyframework-data",
-	"fireflyframework-workflow",
-	"fireflyframework-ecm",
-	"fireflyframework-ecm-esignature-adobe-sign",
-	"fireflyframework-ecm-esignature-docusign",
-	"fireflyframework-ecm-esignature-logalty",
-	"fireflyframework-ecm-storage-aws",
-	"fireflyframework-ecm-storage-azure",
-	"fireflyframework-idp",
-	"fireflyframework-idp-aws-cognito",
-	"fireflyframework-idp-internal-db",
-	"fireflyframework-idp-keycloak",
-	"fireflyframework-notifications",
-	"fireflyframework-notifications-firebase",
-	"fireflyframework-notifications-resend",
-	"fireflyframework-notifications-sendgrid",
-	"fireflyframework-notifications-twilio",
-	"fireflyframework-rule-engine",
-	"fireflyframework-webhooks",
-	"fireflyframework-callbacks",
-	"fireflyframework-config-server",
-	"fireflyframework-application",
-	"fireflyframework-backoffice",
-}
-
 // CloneResult holds the result of a clone operation for a single repo.
 type CloneResult struct {
-	Repo    string
-	Skipped bool
-	Error   error
+	Repo        string
+	Skipped     bool
+	Interrupted bool // Cancelled while cloning; Error is the context's error
+	Error       error
 }
 
 // CloneCallback is invoked after each repo clone with progress info.
@@ -84,42 +44,97 @@ type FetchResult struct {
 // FetchCallback is invoked after each repo fetch.
 type FetchCallback func(repo string, index int, total int, result FetchResult)
 
-// CloneAll clones all framework repos into reposDir (flat order, no callback).
-func CloneAll(org, reposDir, branch string) []CloneResult {
-	results := make([]CloneResult, 0, len(FrameworkRepos))
+// CloneAll clones every catalog repo into reposDir (catalog order, no callback).
+func CloneAll(org, reposDir, branch string) ([]CloneResult, error) {
+	cat, err := catalog.Load(reposDir)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]CloneResult, 0, len(cat.Repos))
 
-	for _, repo := range FrameworkRepos {
-		target := filepath.Join(reposDir, repo)
+	for _, entry := range cat.Repos {
+		target := filepath.Join(reposDir, entry.Name)
 		if _, err := os.Stat(target); err == nil {
-			results = append(results, CloneResult{Repo: repo, Skipped: true})
+			results = append(results, CloneResult{Repo: entry.Name, Skipped: true})
 			continue
 		}
 
-		url := git.RepoURL(org, repo)
-		err := git.CloneQuiet(url, target, branch)
-		results = append(results, CloneResult{Repo: repo, Error: err})
+		err := git.CloneQuiet(context.Background(), entry.CloneURL(org), target, entry.CloneBranch(branch))
+		results = append(results, CloneResult{Repo: entry.Name, Error: err})
 	}
 
-	return results
+	return results, nil
 }
 
-// CloneAllDAG clones repos in DAG layer order, tracking state in the manifest.
-// If manifest is nil, it behaves like the original (no persistence).
-func CloneAllDAG(org, reposDir, branch string, manifest *Manifest, cb CloneCallback) ([]CloneResult, [][]string, error) {
-	g := dag.FrameworkGraph()
+// CloneLayers returns the order in which CloneAllDAG clones repos: the layers
+// of the embedded FrameworkGraph plus the dag.yaml overlays, followed by one
+// extra layer with the catalog repos that are not part of the DAG (e.g.
+// Python and documentation repos), if there are any.
+func CloneLayers(reposDir string) ([][]string, error) {
+	g, err := dag.Embedded(reposDir)
+	if err != nil {
+		return nil, err
+	}
 	layers, err := g.Layers()
+	if err != nil {
+		return nil, err
+	}
+	cat, err := catalog.Load(reposDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var extra []string
+	for _, entry := range cat.Repos {
+		if !g.HasNode(entry.Name) {
+			extra = append(extra, entry.Name)
+		}
+	}
+	if len(extra) > 0 {
+		layers = append(layers, extra)
+	}
+	return layers, nil
+}
+
+// CloneAllDAG clones repos in the order of CloneLayers, tracking state in the
+// manifest. If manifest is nil, it behaves like the original (no persistence).
+// Cloning always uses the embedded FrameworkGraph plus the dag.yaml overlays:
+// the pom.xml files that dag.Load derives the real graph from are not
+// available until repos exist. Clone URLs and branches come from the repo
+// catalog. Repos that are not built with Maven are marked as install-skipped.
+// Cancelling ctx stops the running clone, removes its partial checkout and
+// marks it interrupted; CloneAllDAG then returns the context's error.
+func CloneAllDAG(ctx context.Context, org, reposDir, branch string, manifest *Manifest, cb CloneCallback) ([]CloneResult, [][]string, error) {
+	layers, err := CloneLayers(reposDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	cat, err := catalog.Load(reposDir)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	total := g.NodeCount()
+	total := 0
+	for _, layer := range layers {
+		total += len(layer)
+	}
 	results := make([]CloneResult, 0, total)
 	idx := 0
 
 	for layerIdx, layer := range layers {
 		for _, repo := range layer {
+			if ctx.Err() != nil {
+				return results, layers, ctx.Err()
+			}
 			idx++
 			target := filepath.Join(reposDir, repo)
+			entry, ok := cat.Get(repo)
+			if !ok {
+				entry = catalog.Repo{Name: repo, Kind: catalog.KindMaven}
+			}
+			if manifest != nil && !entry.IsMaven() {
+				manifest.MarkInstallSkipped(repo)
+			}
 
 			// Skip repos that are already cloned successfully in manifest
 			if manifest != nil {
@@ -144,10 +159,17 @@ func CloneAllDAG(org, reposDir, branch string, manifest *Manifest, cb CloneCallb
 					}
 				}
 			} else {
-				url := git.RepoURL(org, repo)
-				cloneErr := git.CloneQuiet(url, target, branch)
+				cloneErr := git.CloneQuiet(ctx, entry.CloneURL(org), target, entry.CloneBranch(branch))
 				r = CloneResult{Repo: repo, Error: cloneErr}
-				if manifest != nil {
+				if cloneErr != nil && ctx.Err() != nil {
+					// A partial checkout would be taken for a finished
+					// clone by the next run.
+					_ = os.RemoveAll(target)
+					r = CloneResult{Repo: repo, Interrupted: true, Error: ctx.Err()}
+					if manifest != nil {
+						manifest.MarkCloneInterrupted(repo)
+					}
+				} else if manifest != nil {
 					manifest.MarkClone(repo, cloneErr)
 					if cloneErr == nil {
 						if sha, shaErr := git.HeadCommit(target); shaErr == nil {
@@ -167,14 +189,18 @@ func CloneAllDAG(org, reposDir, branch string, manifest *Manifest, cb CloneCallb
 		}
 	}
 
-	return results, layers, nil
+	return results, layers, ctx.Err()
 }
 
 // FetchUpdates runs git pull on each already-cloned repo in the given list.
-func FetchUpdates(reposDir string, repos []string, cb FetchCallback) []FetchResult {
+// Cancelling ctx stops the running pull and skips the remaining repos.
+func FetchUpdates(ctx context.Context, reposDir string, repos []string, cb FetchCallback) []FetchResult {
 	results := make([]FetchResult, 0, len(repos))
 
 	for i, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
 		repoDir := filepath.Join(reposDir, repo)
 		var r FetchResult
 		r.Repo = repo
@@ -188,7 +214,7 @@ func FetchUpdates(reposDir string, repos []string, cb FetchCallback) []FetchResu
 			continue
 		}
 
-		r.Error = git.Pull(repoDir)
+		r.Error = git.Pull(ctx, repoDir)
 		results = append(results, r)
 		if cb != nil {
 			cb(repo, i+1, len(repos), r)
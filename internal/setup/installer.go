@@ -15,22 +15,24 @@
 package setup
 
 import (
-	"fmt"
+	"context"
 	"os"
 	"path/filepath"
-	"time"
+	"sync"
 
-	"github.com/fireflyframework/fireflyframework-cli/internal/config"
+	"github.com/fireflyframework/fireflyframework-cli/internal/catalog"
 	"github.com/fireflyframework/fireflyframework-cli/internal/dag"
 	"github.com/fireflyframework/fireflyframework-cli/internal/maven"
+	"github.com/fireflyframework/fireflyframework-cli/internal/runlog"
 )
 
 // InstallResult holds the result of a maven install for a single repo.
 type InstallResult struct {
-	Repo    string
-	Skipped bool
-	Error   error
-	LogFile string // path to build log (populated on failure)
+	Repo        string
+	Skipped     bool
+	Interrupted bool // Cancelled before or while installing; Error is the context's error
+	Error       error
+	LogFile     string // path to build log (populated on failure)
 }
 
 // InstallStartCallback is invoked before each repo install begins.
@@ -39,125 +41,171 @@ type InstallStartCallback func(layer int, repo string, index int, total int)
 // InstallDoneCallback is invoked after each repo install completes.
 type InstallDoneCallback func(layer int, repo string, index int, total int, result InstallResult)
 
-// InstallAll runs mvn clean install on each repo in flat order.
-func InstallAll(reposDir string, skipTests bool) []InstallResult {
-	results := make([]InstallResult, 0, len(FrameworkRepos))
+// InstallOutputCallback receives each line of Maven output of a repo install
+// as it is printed.
+type InstallOutputCallback func(repo, line string)
 
-	for _, repo := range FrameworkRepos {
+// InstallAll runs mvn clean install on each Maven repo of the embedded
+// FrameworkGraph in flat dependency order.
+func InstallAll(reposDir string, skipTests bool) ([]InstallResult, error) {
+	g, err := dag.Embedded(reposDir)
+	if err != nil {
+		return nil, err
+	}
+	order, err := g.FlatOrder()
+	if err != nil {
+		return nil, err
+	}
+	cat, err := catalog.Load(reposDir)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]InstallResult, 0, len(order))
+
+	for _, repo := range order {
+		if !cat.Buildable(repo) {
+			results = append(results, InstallResult{Repo: repo, Skipped: true})
+			continue
+		}
 		dir := filepath.Join(reposDir, repo)
 		err := maven.InstallQuiet(dir, skipTests)
 		results = append(results, InstallResult{Repo: repo, Error: err})
 	}
 
-	return results
+	return results, nil
 }
 
 // InstallAllDAG installs repos in DAG layer order, tracking state in the manifest.
+// Up to jobs repos are installed concurrently, each starting once its own
+// dependencies are installed and each Maven build with a heapMB -Xmx budget (0 keeps Maven's default); callbacks are never
+// invoked concurrently. The budget replaces an -Xmx already in MAVEN_OPTS
+// only when heapSet. The overlay's Maven settings of each repo are merged
+// over mvn.
 // If reposFilter is non-nil, only repos in that set are built (others are skipped).
-// If manifest is nil, no state is persisted.
-func InstallAllDAG(reposDir, javaHome string, skipTests bool, manifest *Manifest, reposFilter map[string]bool, onStart InstallStartCallback, onDone InstallDoneCallback) ([]InstallResult, [][]string, error) {
-	g := dag.FrameworkGraph()
+// If manifest is nil, no state is persisted. The Maven output of every
+// built repo is streamed to its log in logs unless it is nil, and to
+// onOutput line by line unless it is nil.
+// Cancelling ctx terminates the running Maven builds, marks their repos
+// interrupted and starts no new ones; InstallAllDAG then returns the
+// results together with the context's error.
+func InstallAllDAG(ctx context.Context, reposDir, javaHome string, skipTests bool, jobs, heapMB int, heapSet bool, mvn maven.Settings, manifest *Manifest, logs *runlog.Run, reposFilter map[string]bool, onStart InstallStartCallback, onDone InstallDoneCallback, onOutput InstallOutputCallback) ([]InstallResult, [][]string, error) {
+	g, err := dag.Load(reposDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	overlay, err := dag.LoadOverlay(reposDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	cat, err := catalog.Load(reposDir)
+	if err != nil {
+		return nil, nil, err
+	}
 	layers, err := g.Layers()
 	if err != nil {
 		return nil, nil, err
 	}
 
 	total := g.NodeCount()
-	results := make([]InstallResult, 0, total)
-	idx := 0
-
-	for layerIdx, layer := range layers {
-		for _, repo := range layer {
-			idx++
-			dir := filepath.Join(reposDir, repo)
-
-			// If we have a filter, skip repos not in the set
-			if reposFilter != nil && !reposFilter[repo] {
-				r := InstallResult{Repo: repo, Skipped: true}
-				results = append(results, r)
-				if onDone != nil {
-					onDone(layerIdx, repo, idx, total, r)
-				}
-				continue
-			}
+	results := make([]InstallResult, total)
+	var mu sync.Mutex // serializes callbacks and manifest saves
+
+	finish := func(layerIdx int, repo string, idx int, r InstallResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[idx-1] = r
+		if onDone != nil {
+			onDone(layerIdx, repo, idx, total, r)
+		}
+	}
 
-			// If manifest shows this repo already succeeded, skip it
-			if manifest != nil && reposFilter == nil {
-				rs := manifest.Repo(repo)
-				if rs.InstallStatus == StatusSuccess {
-					r := InstallResult{Repo: repo, Skipped: true}
-					results = append(results, r)
-					if onDone != nil {
-						onDone(layerIdx, repo, idx, total, r)
-					}
-					continue
-				}
-			}
+	// Every repo is attempted even when one of its dependencies fails; the
+	// retry loop in setup picks up the failures.
+	err = dag.Schedule(g, jobs, dag.ForceContinue, func(layerIdx int, repo string, idx int) bool {
+		dir := filepath.Join(reposDir, repo)
 
-			if onStart != nil {
-				onStart(layerIdx, repo, idx, total)
-			}
+		if ctx.Err() != nil {
+			finish(layerIdx, repo, idx, InstallResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return false
+		}
 
-			// Skip repos that have no pom.xml (empty or uninitialized)
-			var installErr error
-			var buildOutput []byte
-			pomPath := filepath.Join(dir, "pom.xml")
-			if _, serr := os.Stat(pomPath); os.IsNotExist(serr) {
-				// no pom.xml — skip silently
-				if manifest != nil {
-					manifest.MarkInstallSkipped(repo)
-				}
-			} else if javaHome != "" {
-				buildOutput, installErr = maven.InstallQuietWithJavaOutput(dir, javaHome, skipTests)
-			} else {
-				buildOutput, installErr = maven.InstallQuietOutput(dir, skipTests)
-			}
+		// If we have a filter, skip repos not in the set
+		if reposFilter != nil && !reposFilter[repo] {
+			finish(layerIdx, repo, idx, InstallResult{Repo: repo, Skipped: true})
+			return true
+		}
 
-			if manifest != nil && installErr != nil {
-				manifest.MarkInstall(repo, installErr)
-			} else if manifest != nil {
-				manifest.MarkInstall(repo, nil)
+		// If manifest shows this repo already succeeded, skip it
+		if manifest != nil && reposFilter == nil {
+			rs := manifest.Repo(repo)
+			if rs.InstallStatus == StatusSuccess {
+				finish(layerIdx, repo, idx, InstallResult{Repo: repo, Skipped: true})
+				return true
 			}
+		}
 
-			// Write build log on failure
-			var logFile string
-			if installErr != nil && len(buildOutput) > 0 {
-				logFile = writeBuildLog(repo, buildOutput)
+		if onStart != nil {
+			mu.Lock()
+			onStart(layerIdx, repo, idx, total)
+			mu.Unlock()
+		}
+
+		// Skip repos that have no pom.xml (empty or uninitialized), that
+		// the overlay excludes from builds or that the catalog does not
+		// declare as Maven repos
+		var installErr error
+		skipped := false
+		settings := overlay.Settings(repo)
+		repoSkipTests := settings.ResolveSkipTests(skipTests)
+		pomPath := filepath.Join(dir, "pom.xml")
+		if _, serr := os.Stat(pomPath); os.IsNotExist(serr) || settings.Skip || !cat.Buildable(repo) {
+			// no pom.xml — skip silently
+			skipped = true
+			if manifest != nil {
+				manifest.MarkInstallSkipped(repo)
 			}
+		} else {
+			runOpts := maven.RunOptions{JavaHome: javaHome, HeapMB: heapMB, HeapSet: heapSet, Maven: mvn.Merge(settings.Maven()), LogFile: logs.Path(repo)}
+			if onOutput != nil {
+				runOpts.Tail = func(line string) {
+					mu.Lock()
+					defer mu.Unlock()
+					onOutput(repo, line)
+				}
+			}
+			_, installErr = maven.InstallWithOptions(ctx, dir, runOpts, repoSkipTests)
+		}
 
-			r := InstallResult{Repo: repo, Error: installErr, LogFile: logFile}
-			results = append(results, r)
+		if installErr != nil && ctx.Err() != nil {
+			logs.Record(repo, ctx.Err())
 			if manifest != nil {
+				manifest.MarkInstallInterrupted(repo)
 				_ = manifest.Save()
 			}
-			if onDone != nil {
-				onDone(layerIdx, repo, idx, total, r)
-			}
+			finish(layerIdx, repo, idx, InstallResult{Repo: repo, Interrupted: true, Error: ctx.Err()})
+			return false
 		}
-	}
 
-	return results, layers, nil
-}
+		if manifest != nil && installErr != nil {
+			manifest.MarkInstall(repo, installErr)
+		} else if manifest != nil {
+			manifest.MarkInstall(repo, nil)
+		}
 
-// LogsDir returns the path to the build logs directory (~/.flywork/logs).
-func LogsDir() string {
-	return filepath.Join(config.FlyworkHome(), "logs")
-}
+		var logFile string
+		if !skipped {
+			logFile = logs.Record(repo, installErr)
+		}
 
-// writeBuildLog writes Maven output to ~/.flywork/logs/<repo>.log and returns
-// the log file path. Returns "" if writing fails.
-func writeBuildLog(repo string, output []byte) string {
-	logsDir := LogsDir()
-	if err := os.MkdirAll(logsDir, 0755); err != nil {
-		return ""
+		if manifest != nil {
+			_ = manifest.Save()
+		}
+		finish(layerIdx, repo, idx, InstallResult{Repo: repo, Skipped: skipped, Error: installErr, LogFile: logFile})
+		return installErr == nil
+	}, nil)
+	if err != nil {
+		return nil, nil, err
 	}
-	logFile := filepath.Join(logsDir, repo+".log")
 
-	header := fmt.Sprintf("=== Build log for %s ===\n=== %s ===\n\n", repo, time.Now().Format(time.RFC3339))
-	content := append([]byte(header), output...)
-
-	if err := os.WriteFile(logFile, content, 0644); err != nil {
-		return ""
-	}
-	return logFile
+	return results, layers, ctx.Err()
 }
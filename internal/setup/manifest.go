@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/fireflyframework/fireflyframework-cli/internal/config"
@@ -32,6 +33,10 @@ const (
 	StatusFailed  Status = "failed"
 	StatusSkipped Status = "skipped"
 
+	// StatusInterrupted marks a clone or install that was cancelled while
+	// running; it is retried like a failure.
+	StatusInterrupted Status = "interrupted"
+
 	ManifestFile = "setup-manifest.json"
 	ManifestVer  = 1
 )
@@ -46,7 +51,8 @@ type RepoState struct {
 	LastAttempt   time.Time `json:"last_attempt"`
 }
 
-// Manifest is the top-level setup manifest persisted to disk.
+// Manifest is the top-level setup manifest persisted to disk. Repo, the Mark
+// methods and Save are safe for concurrent use.
 type Manifest struct {
 	Version     int                   `json:"version"`
 	StartedAt   time.Time             `json:"started_at"`
@@ -55,7 +61,8 @@ type Manifest struct {
 	SkipTests   bool                  `json:"skip_tests"`
 	Repos       map[string]*RepoState `json:"repos"`
 
-	path string // file path (not serialised)
+	path string     // file path (not serialised)
+	mu   sync.Mutex // guards Repos while installs run concurrently
 }
 
 // DefaultManifestPath returns ~/.flywork/setup-manifest.json.
@@ -99,6 +106,8 @@ func LoadManifest(path string) (*Manifest, error) {
 
 // Save writes the manifest to disk.
 func (m *Manifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.path == "" {
 		m.path = DefaultManifestPath()
 	}
@@ -109,7 +118,7 @@ func (m *Manifest) Save() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(m.path, data, 0644)
+	return config.WriteFileAtomic(m.path, data, 0644)
 }
 
 // SetPath overrides the file path for this manifest.
@@ -119,6 +128,12 @@ func (m *Manifest) SetPath(p string) {
 
 // Repo returns the state for a repo, creating it if absent.
 func (m *Manifest) Repo(name string) *RepoState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.repo(name)
+}
+
+func (m *Manifest) repo(name string) *RepoState {
 	rs, ok := m.Repos[name]
 	if !ok {
 		rs = &RepoState{CloneStatus: StatusPending, InstallStatus: StatusPending}
@@ -129,7 +144,9 @@ func (m *Manifest) Repo(name string) *RepoState {
 
 // MarkClone records the clone result for a repo.
 func (m *Manifest) MarkClone(repo string, err error) {
-	rs := m.Repo(repo)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rs := m.repo(repo)
 	rs.LastAttempt = time.Now()
 	if err != nil {
 		rs.CloneStatus = StatusFailed
@@ -142,14 +159,18 @@ func (m *Manifest) MarkClone(repo string, err error) {
 
 // MarkCloneSkipped marks a repo as skipped (already cloned).
 func (m *Manifest) MarkCloneSkipped(repo string) {
-	rs := m.Repo(repo)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rs := m.repo(repo)
 	rs.CloneStatus = StatusSkipped
 	rs.LastAttempt = time.Now()
 }
 
 // MarkInstall records the install result for a repo.
 func (m *Manifest) MarkInstall(repo string, err error) {
-	rs := m.Repo(repo)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rs := m.repo(repo)
 	rs.LastAttempt = time.Now()
 	if err != nil {
 		rs.InstallStatus = StatusFailed
@@ -160,9 +181,31 @@ func (m *Manifest) MarkInstall(repo string, err error) {
 	}
 }
 
+// MarkCloneInterrupted records that a repo's clone was cancelled.
+func (m *Manifest) MarkCloneInterrupted(repo string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rs := m.repo(repo)
+	rs.LastAttempt = time.Now()
+	rs.CloneStatus = StatusInterrupted
+	rs.CloneError = "clone interrupted"
+}
+
+// MarkInstallInterrupted records that a repo's install was cancelled.
+func (m *Manifest) MarkInstallInterrupted(repo string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rs := m.repo(repo)
+	rs.LastAttempt = time.Now()
+	rs.InstallStatus = StatusInterrupted
+	rs.InstallError = "install interrupted"
+}
+
 // MarkInstallSkipped marks a repo install as skipped.
 func (m *Manifest) MarkInstallSkipped(repo string) {
-	rs := m.Repo(repo)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rs := m.repo(repo)
 	rs.InstallStatus = StatusSkipped
 	rs.LastAttempt = time.Now()
 }
@@ -173,11 +216,12 @@ func (m *Manifest) MarkComplete() {
 	m.CompletedAt = &now
 }
 
-// PendingClones returns repo names with clone_status == pending or failed.
+// PendingClones returns repo names with clone_status == pending, failed or
+// interrupted.
 func (m *Manifest) PendingClones() []string {
 	var out []string
 	for name, rs := range m.Repos {
-		if rs.CloneStatus == StatusPending || rs.CloneStatus == StatusFailed {
+		if rs.CloneStatus == StatusPending || rs.CloneStatus == StatusFailed || rs.CloneStatus == StatusInterrupted {
 			out = append(out, name)
 		}
 	}
@@ -195,11 +239,12 @@ func (m *Manifest) FailedClones() []string {
 	return out
 }
 
-// PendingInstalls returns repo names with install_status == pending or failed.
+// PendingInstalls returns repo names with install_status == pending, failed
+// or interrupted.
 func (m *Manifest) PendingInstalls() []string {
 	var out []string
 	for name, rs := range m.Repos {
-		if rs.InstallStatus == StatusPending || rs.InstallStatus == StatusFailed {
+		if rs.InstallStatus == StatusPending || rs.InstallStatus == StatusFailed || rs.InstallStatus == StatusInterrupted {
 			out = append(out, name)
 		}
 	}
@@ -286,14 +331,15 @@ func (m *Manifest) Summary() ManifestSummary {
 	return s
 }
 
-// ResetFailed resets all failed clone/install statuses back to pending.
+// ResetFailed resets all failed and interrupted clone/install statuses back
+// to pending.
 func (m *Manifest) ResetFailed() {
 	for _, rs := range m.Repos {
-		if rs.CloneStatus == StatusFailed {
+		if rs.CloneStatus == StatusFailed || rs.CloneStatus == StatusInterrupted {
 			rs.CloneStatus = StatusPending
 			rs.CloneError = ""
 		}
-		if rs.InstallStatus == StatusFailed {
+		if rs.InstallStatus == StatusFailed || rs.InstallStatus == StatusInterrupted {
 			rs.InstallStatus = StatusPending
 			rs.InstallError = ""
 		}
@@ -17,6 +17,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -196,6 +197,139 @@ func (s *Spinner) Stop(success bool) {
 	}
 }
 
+// ─────────────────────────────────────────────────────────────────────────────
+// JobSpinner — one spinner line for several concurrently running jobs
+// ─────────────────────────────────────────────────────────────────────────────
+
+// JobSpinner shows the jobs that are currently running on a single spinner
+// line and prints a ✓/✗ line with the elapsed time as each one finishes. Its
+// methods are safe for concurrent use. With one job at a time it looks
+// exactly like a Spinner.
+type JobSpinner struct {
+	verb    string
+	frames  []string
+	mu      sync.Mutex
+	running []string
+	started map[string]time.Time
+	done    chan bool
+	frame   int
+	width   int // Width of the job prefix of Log lines
+}
+
+// NewJobSpinner creates a spinner that renders "<verb> <job>..." lines.
+func NewJobSpinner(verb string) *JobSpinner {
+	return &JobSpinner{
+		verb:    verb,
+		frames:  []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		started: make(map[string]time.Time),
+	}
+}
+
+// Add marks a job as running, starting the animation if it is the first one.
+func (js *JobSpinner) Add(job string) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.running = append(js.running, job)
+	js.started[job] = time.Now()
+	if js.done == nil {
+		js.done = make(chan bool)
+		go js.animate(js.done)
+	}
+}
+
+// Done prints the outcome of a job and removes it from the spinner line.
+// Jobs that were never added are ignored.
+func (js *JobSpinner) Done(job string, success bool) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if _, ok := js.started[job]; !ok {
+		return
+	}
+	for i, r := range js.running {
+		if r == job {
+			js.running = append(js.running[:i], js.running[i+1:]...)
+			break
+		}
+	}
+	elapsed := time.Since(js.started[job]).Truncate(time.Second)
+	delete(js.started, job)
+	if len(js.running) == 0 && js.done != nil {
+		close(js.done)
+		js.done = nil
+	}
+
+	mark := StyleSuccess.Render("✓")
+	if !success {
+		mark = StyleError.Render("✗")
+	}
+	fmt.Printf("\r\033[K  %s %s %s...%s\n", mark, js.verb, job, StyleMuted.Render(fmt.Sprintf(" (%s)", elapsed)))
+}
+
+// Log prints a line of output of a running job above the spinner line,
+// prefixed with the job, and redraws the spinner below it.
+func (js *JobSpinner) Log(job, line string) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.width = max(js.width, len(job))
+	fmt.Printf("\r\033[K    %s %s\n", StyleMuted.Render(fmt.Sprintf("%-*s │", js.width, job)), line)
+	if len(js.running) > 0 {
+		fmt.Printf("  %s", js.line(js.frame))
+	}
+}
+
+// Stop clears the spinner line and forgets the running jobs without
+// printing their outcome, e.g. when the jobs were cancelled.
+func (js *JobSpinner) Stop() {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if js.done != nil {
+		close(js.done)
+		js.done = nil
+	}
+	js.running = nil
+	js.started = make(map[string]time.Time)
+	fmt.Print("\r\033[K")
+}
+
+func (js *JobSpinner) animate(done chan bool) {
+	for i := 0; ; i++ {
+		js.mu.Lock()
+		select {
+		case <-done:
+			js.mu.Unlock()
+			return
+		default:
+		}
+		js.frame = i
+		fmt.Printf("\r\033[K  %s", js.line(i))
+		js.mu.Unlock()
+		time.Sleep(80 * time.Millisecond)
+	}
+}
+
+// maxSpinnerJobs is the number of other running jobs named on the spinner
+// line, which must fit on one terminal line.
+const maxSpinnerJobs = 3
+
+// line renders the spinner line; the caller holds js.mu.
+func (js *JobSpinner) line(frame int) string {
+	if len(js.running) == 0 {
+		return ""
+	}
+	oldest := js.started[js.running[0]]
+	elapsed := time.Since(oldest).Truncate(time.Second)
+	label := js.running[0]
+	if n := len(js.running); n > 1 {
+		others := js.running[1:]
+		if len(others) > maxSpinnerJobs {
+			others = append(others[:maxSpinnerJobs:maxSpinnerJobs], "…")
+		}
+		label = fmt.Sprintf("%s (+%d: %s)", js.running[0], n-1, strings.Join(others, ", "))
+	}
+	return fmt.Sprintf("%s %s %s...%s", StylePrimary.Render(js.frames[frame%len(js.frames)]), js.verb, label,
+		StyleMuted.Render(fmt.Sprintf(" (%s)", elapsed)))
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // ProgressBar — inline progress indicator with bar, count and percentage
 // ─────────────────────────────────────────────────────────────────────────────
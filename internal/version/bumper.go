@@ -52,7 +52,10 @@ type BumpCallback func(idx, total int, result RepoResult)
 
 // BumpAll iterates all repos in DAG order, updating pom.xml versions.
 func BumpAll(opts BumpOptions, cb BumpCallback) ([]RepoResult, error) {
-	g := dag.FrameworkGraph()
+	g, err := dag.LoadFramework(opts.ReposDir)
+	if err != nil {
+		return nil, err
+	}
 	order, err := g.FlatOrder()
 	if err != nil {
 		return nil, fmt.Errorf("dependency graph error: %w", err)
@@ -157,17 +160,10 @@ func bumpRepo(opts BumpOptions, repo string) RepoResult {
 	return r
 }
 
-// BumpGenAI updates version references in the GenAI Python module.
-func BumpGenAI(repoDir, oldVer, newVer string, dryRun bool) error {
-	files := []string{
-		"pyproject.toml",
-		"src/fireflyframework_genai/_version.py",
-		"scripts/install.sh",
-		"scripts/uninstall.sh",
-		"scripts/install.ps1",
-		"scripts/uninstall.ps1",
-	}
-
+// BumpVersionFiles replaces oldVer with newVer in the given files of a
+// repository, e.g. the version files a catalog entry declares for a Python
+// module. Missing files are ignored.
+func BumpVersionFiles(repoDir string, files []string, oldVer, newVer string, dryRun bool) error {
 	for _, f := range files {
 		path := filepath.Join(repoDir, f)
 		if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -44,7 +44,10 @@ type VersionReport struct {
 
 // CheckAll scans all repos and returns a version consistency report.
 func CheckAll(reposDir string) (*VersionReport, error) {
-	g := dag.FrameworkGraph()
+	g, err := dag.LoadFramework(reposDir)
+	if err != nil {
+		return nil, err
+	}
 	order, err := g.FlatOrder()
 	if err != nil {
 		return nil, err
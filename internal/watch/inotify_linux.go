@@ -0,0 +1,147 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE | syscall.IN_ATTRIB |
+	syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inotifyBackend watches every directory of the tree with inotify, adding
+// watches for directories created later.
+type inotifyBackend struct {
+	root   string
+	ignore func(string, bool) bool
+	fd     int
+	file   *os.File
+
+	mu   sync.Mutex
+	dirs map[int]string // watch descriptor → directory
+}
+
+func newNativeBackend(root string, ignore func(string, bool) bool) (backend, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	b := &inotifyBackend{
+		root:   root,
+		ignore: ignore,
+		fd:     fd,
+		// A non-blocking descriptor is pollable, so Close unblocks Read.
+		file: os.NewFile(uintptr(fd), "inotify"),
+		dirs: make(map[int]string),
+	}
+	var addErr error
+	walkDirs(root, ignore, func(dir string) {
+		if addErr == nil {
+			addErr = b.add(dir)
+		}
+	})
+	if addErr != nil {
+		b.file.Close()
+		return nil, addErr
+	}
+	return b, nil
+}
+
+func (b *inotifyBackend) name() string {
+	return "inotify"
+}
+
+func (b *inotifyBackend) add(dir string) error {
+	wd, err := syscall.InotifyAddWatch(b.fd, dir, inotifyMask)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.dirs[wd] = dir
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *inotifyBackend) run(ctx context.Context, changes chan<- string) {
+	go func() {
+		<-ctx.Done()
+		b.file.Close()
+	}()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := b.file.Read(buf)
+		if err != nil {
+			return
+		}
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameBytes := buf[off+syscall.SizeofInotifyEvent : off+syscall.SizeofInotifyEvent+int(ev.Len)]
+			off += syscall.SizeofInotifyEvent + int(ev.Len)
+
+			if ev.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				// Events were lost; report the root so that everything is
+				// considered changed.
+				send(ctx, changes, ".")
+				continue
+			}
+			b.mu.Lock()
+			dir, ok := b.dirs[int(ev.Wd)]
+			if ev.Mask&syscall.IN_IGNORED != 0 {
+				delete(b.dirs, int(ev.Wd))
+			}
+			b.mu.Unlock()
+			if !ok {
+				continue
+			}
+
+			name := string(nameBytes)
+			for i := 0; i < len(name); i++ {
+				if name[i] == 0 {
+					name = name[:i]
+					break
+				}
+			}
+			path := filepath.Join(dir, name)
+			rel := relPath(b.root, path)
+			isDir := ev.Mask&syscall.IN_ISDIR != 0
+			if name == "" || b.ignore(rel, isDir) {
+				continue
+			}
+
+			if isDir && ev.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+				// Watch the new directory and report what it already
+				// contains, which was created before the watch existed.
+				walkDirs(path, func(r string, d bool) bool {
+					return b.ignore(relPath(b.root, filepath.Join(path, r)), d)
+				}, func(sub string) {
+					_ = b.add(sub)
+					entries, _ := os.ReadDir(sub)
+					for _, e := range entries {
+						if !e.IsDir() {
+							send(ctx, changes, relPath(b.root, filepath.Join(sub, e.Name())))
+						}
+					}
+				})
+			}
+			send(ctx, changes, rel)
+		}
+	}
+}
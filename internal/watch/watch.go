@@ -0,0 +1,225 @@
+// Copyright 2024-2026 Firefly Software Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watch reports file changes below a directory tree. On Linux it
+// uses inotify; elsewhere, or when inotify is unavailable (e.g. the watch
+// limit is exhausted), it polls modification times. No external tools are
+// required.
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Options configures Watch.
+type Options struct {
+	// Debounce is how long the tree must stay quiet before a batch of
+	// changes is reported (default 500ms).
+	Debounce time.Duration
+	// PollInterval is the scan interval of the polling fallback
+	// (default 1s).
+	PollInterval time.Duration
+	// Ignore reports whether a path, relative to the root and using forward
+	// slashes, is not watched. An ignored directory is not descended into.
+	Ignore func(rel string, isDir bool) bool
+}
+
+// backend delivers raw change notifications as root-relative paths.
+type backend interface {
+	run(ctx context.Context, changes chan<- string)
+	name() string
+}
+
+// Watcher reports batches of changed paths below a root directory.
+type Watcher struct {
+	root    string
+	opts    Options
+	backend backend
+}
+
+// New prepares a watcher for root. The initial directory scan happens here,
+// so changes made after New returns are reported once Run is called.
+func New(root string, opts Options) (*Watcher, error) {
+	if opts.Debounce <= 0 {
+		opts.Debounce = 500 * time.Millisecond
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Ignore == nil {
+		opts.Ignore = func(string, bool) bool { return false }
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	b, err := newNativeBackend(root, opts.Ignore)
+	if err != nil {
+		b = newPollBackend(root, opts.Ignore, opts.PollInterval)
+	}
+	return &Watcher{root: root, opts: opts, backend: b}, nil
+}
+
+// Method describes how changes are detected ("inotify" or "polling").
+func (w *Watcher) Method() string {
+	return w.backend.name()
+}
+
+// Run watches until ctx is done, sending each debounced batch of changed
+// paths (relative to the root, sorted, without duplicates) to batches. It
+// closes batches when it returns.
+func (w *Watcher) Run(ctx context.Context, batches chan<- []string) {
+	defer close(batches)
+	changes := make(chan string, 256)
+	go w.backend.run(ctx, changes)
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case rel := <-changes:
+			pending[rel] = true
+			timer.Reset(w.opts.Debounce)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			batch := make([]string, 0, len(pending))
+			for rel := range pending {
+				batch = append(batch, rel)
+			}
+			sort.Strings(batch)
+			pending = make(map[string]bool)
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// relPath returns path relative to root with forward slashes.
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// walkDirs calls fn for root and every directory below it that is not
+// ignored.
+func walkDirs(root string, ignore func(string, bool) bool, fn func(dir string)) {
+	_ = filepath.WalkDir(root, func(path string, de os.DirEntry, err error) error {
+		if err != nil || !de.IsDir() {
+			return nil
+		}
+		if path != root && ignore(relPath(root, path), true) {
+			return filepath.SkipDir
+		}
+		fn(path)
+		return nil
+	})
+}
+
+// pollBackend detects changes by comparing modification times and sizes.
+type pollBackend struct {
+	root     string
+	ignore   func(string, bool) bool
+	interval time.Duration
+	state    map[string]fileState
+}
+
+type fileState struct {
+	mod  time.Time
+	size int64
+}
+
+func newPollBackend(root string, ignore func(string, bool) bool, interval time.Duration) *pollBackend {
+	p := &pollBackend{root: root, ignore: ignore, interval: interval}
+	p.state = p.scan()
+	return p
+}
+
+func (p *pollBackend) name() string {
+	return "polling"
+}
+
+func (p *pollBackend) scan() map[string]fileState {
+	state := make(map[string]fileState)
+	_ = filepath.WalkDir(p.root, func(path string, de os.DirEntry, err error) error {
+		if err != nil || path == p.root {
+			return nil
+		}
+		rel := relPath(p.root, path)
+		if p.ignore(rel, de.IsDir()) {
+			if de.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if de.IsDir() {
+			return nil
+		}
+		if info, err := de.Info(); err == nil {
+			state[rel] = fileState{info.ModTime(), info.Size()}
+		}
+		return nil
+	})
+	return state
+}
+
+func (p *pollBackend) run(ctx context.Context, changes chan<- string) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		next := p.scan()
+		for rel, st := range next {
+			if old, ok := p.state[rel]; !ok || old != st {
+				send(ctx, changes, rel)
+			}
+		}
+		for rel := range p.state {
+			if _, ok := next[rel]; !ok {
+				send(ctx, changes, rel)
+			}
+		}
+		p.state = next
+	}
+}
+
+func send(ctx context.Context, changes chan<- string, rel string) {
+	if strings.HasPrefix(rel, "../") {
+		return
+	}
+	select {
+	case changes <- rel:
+	case <-ctx.Done():
+	}
+}